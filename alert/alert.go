@@ -0,0 +1,268 @@
+// Package alert evaluates threshold rules against a report, on its own values or on their change from the previous report.
+package alert
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pokt-network/relay_counter/report"
+)
+
+const (
+	MetricTotalRelays    = "total_relays"
+	MetricChallenges     = "challenges"
+	MetricMinted         = "minted"
+	MetricGoodTxs        = "good_txs"
+	MetricBadTxs         = "bad_txs"
+	MetricProofTxs       = "proof_txs"
+	MetricFilteredRelays = "filtered_relays"
+	MetricNodeRelays     = "node_relays"
+	MetricAppRelays      = "app_relays"
+	MetricChainRelays    = "chain_relays"
+	MetricGroupRelays    = "group_relays"
+	SeverityWarning      = "warning"
+	SeverityCritical     = "critical"
+	ConditionAbove       = "above"
+	ConditionBelow       = "below"
+	ConditionChangeAbove = "change_above"
+	ConditionChangeBelow = "change_below"
+)
+
+// A threshold on a metric of the report. The per key metrics (node, app, chain and group relays) are checked for Key, or
+// for every key of the report and of the previous report when Key is empty. The changes are percents from the previous
+// report, e.g. change_below -30 for a drop of more than 30%
+type Rule struct {
+	Name        string   `json:"name"`
+	Metric      string   `json:"metric"`
+	Key         string   `json:"key,omitempty"`
+	Above       *float64 `json:"above,omitempty"`
+	Below       *float64 `json:"below,omitempty"`
+	ChangeAbove *float64 `json:"change_above,omitempty"`
+	ChangeBelow *float64 `json:"change_below,omitempty"`
+	Severity    string   `json:"severity,omitempty"`
+}
+
+// A rule whose threshold was crossed
+type Alert struct {
+	Rule      string             `json:"rule"`
+	Severity  string             `json:"severity"`
+	Metric    string             `json:"metric"`
+	Key       string             `json:"key,omitempty"`
+	Condition string             `json:"condition"`
+	Threshold float64            `json:"threshold"`
+	Value     int64              `json:"value"`
+	Previous  *int64             `json:"previous,omitempty"`
+	Change    *float64           `json:"change_percent,omitempty"`
+	Range     report.BlockReport `json:"range"`
+	// the report is missing some heights, its values may be low
+	Incomplete bool      `json:"incomplete,omitempty"`
+	Message    string    `json:"message"`
+	Time       time.Time `json:"time"`
+}
+
+func perKey(metric string) bool {
+	switch metric {
+	case MetricNodeRelays, MetricAppRelays, MetricChainRelays, MetricGroupRelays:
+		return true
+	}
+	return false
+}
+
+func (r Rule) Validate() (errs []error) {
+	if r.Name == "" {
+		errs = append(errs, NewMissingRuleNameError())
+	}
+	switch r.Metric {
+	case MetricTotalRelays, MetricChallenges, MetricMinted, MetricGoodTxs, MetricBadTxs, MetricProofTxs, MetricFilteredRelays:
+		if r.Key != "" {
+			errs = append(errs, NewUnexpectedKeyError(r.Metric))
+		}
+	case MetricNodeRelays, MetricAppRelays, MetricChainRelays, MetricGroupRelays:
+	default:
+		errs = append(errs, NewInvalidMetricError(r.Metric))
+	}
+	if r.Above == nil && r.Below == nil && r.ChangeAbove == nil && r.ChangeBelow == nil {
+		errs = append(errs, NewMissingConditionError())
+	}
+	switch r.Severity {
+	case "", SeverityWarning, SeverityCritical:
+	default:
+		errs = append(errs, NewInvalidSeverityError(r.Severity))
+	}
+	return errs
+}
+
+func (r Rule) severity() string {
+	if r.Severity == "" {
+		return SeverityWarning
+	}
+	return r.Severity
+}
+
+// The values of the metric by key, keyed by "" for the metrics of the whole report
+func values(metric string, result report.Report) map[string]int64 {
+	switch metric {
+	case MetricTotalRelays:
+		return map[string]int64{"": result.TotalRelaysCompleted}
+	case MetricChallenges:
+		return map[string]int64{"": result.TotalChallengesCompleted}
+	case MetricMinted:
+		return map[string]int64{"": result.TotalMinted}
+	case MetricGoodTxs:
+		return map[string]int64{"": result.TotalGoodTxs}
+	case MetricBadTxs:
+		return map[string]int64{"": result.TotalBadTxs}
+	case MetricProofTxs:
+		return map[string]int64{"": result.TotalProofTxs}
+	case MetricFilteredRelays:
+		if result.Filtered == nil {
+			return map[string]int64{"": 0}
+		}
+		return map[string]int64{"": result.Filtered.TotalRelaysCompleted}
+	}
+	byKey := make(map[string]int64)
+	switch metric {
+	case MetricNodeRelays:
+		for address, nodeReport := range result.NodeReports {
+			byKey[address] = nodeReport.TotalRelays
+		}
+	case MetricAppRelays:
+		for address, appReport := range result.AppReports {
+			byKey[address] = appReport.TotalRelays
+		}
+	case MetricChainRelays:
+		byKey = report.RelaysByChain(result)
+	case MetricGroupRelays:
+		for name, groupReport := range result.GroupReports {
+			byKey[name] = groupReport.TotalRelays
+		}
+	}
+	return byKey
+}
+
+// Evaluates the rules against the report, the change conditions are skipped without a previous report or when the
+// previous value is 0. The alerts are sorted by rule then key
+func Evaluate(rules []Rule, current report.Report, previous *report.Report) (alerts []Alert) {
+	now := time.Now().UTC()
+	for _, rule := range rules {
+		cur := values(rule.Metric, current)
+		var prev map[string]int64
+		if previous != nil {
+			prev = values(rule.Metric, *previous)
+		}
+		keys := []string{rule.Key}
+		if perKey(rule.Metric) && rule.Key == "" {
+			keys = unionKeys(cur, prev)
+		}
+		for _, key := range keys {
+			value := lookup(cur, key)
+			a := Alert{
+				Rule:       rule.Name,
+				Severity:   rule.severity(),
+				Metric:     rule.Metric,
+				Key:        key,
+				Value:      value,
+				Range:      current.BlockReport,
+				Incomplete: current.Incomplete,
+				Time:       now,
+			}
+			if previous != nil {
+				p := lookup(prev, key)
+				a.Previous = &p
+				if p != 0 {
+					change := float64(value-p) * 100 / float64(p)
+					a.Change = &change
+				}
+			}
+			for _, check := range []struct {
+				condition string
+				threshold *float64
+				value     *float64
+				crossed   func(v, t float64) bool
+			}{
+				{ConditionAbove, rule.Above, float(value), func(v, t float64) bool { return v > t }},
+				{ConditionBelow, rule.Below, float(value), func(v, t float64) bool { return v < t }},
+				{ConditionChangeAbove, rule.ChangeAbove, a.Change, func(v, t float64) bool { return v > t }},
+				{ConditionChangeBelow, rule.ChangeBelow, a.Change, func(v, t float64) bool { return v < t }},
+			} {
+				if check.threshold == nil || check.value == nil || !check.crossed(*check.value, *check.threshold) {
+					continue
+				}
+				a.Condition, a.Threshold = check.condition, *check.threshold
+				a.Message = a.message()
+				alerts = append(alerts, a)
+			}
+		}
+	}
+	return alerts
+}
+
+// Addresses are matched whatever their case
+func lookup(m map[string]int64, key string) int64 {
+	if v, ok := m[key]; ok {
+		return v
+	}
+	for k, v := range m {
+		if strings.EqualFold(k, key) {
+			return v
+		}
+	}
+	return 0
+}
+
+func float(v int64) *float64 {
+	f := float64(v)
+	return &f
+}
+
+func unionKeys(maps ...map[string]int64) []string {
+	seen := make(map[string]bool)
+	for _, m := range maps {
+		for key := range m {
+			seen[key] = true
+		}
+	}
+	keys := make([]string, 0, len(seen))
+	for key := range seen {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (a Alert) message() string {
+	subject := a.Metric
+	if a.Key != "" {
+		subject += " of " + a.Key
+	}
+	switch a.Condition {
+	case ConditionChangeAbove, ConditionChangeBelow:
+		return fmt.Sprintf("%s: %s changed by %.1f%% (%d to %d), threshold %s %.1f%%", a.Rule, subject, *a.Change, *a.Previous, a.Value, a.Condition, a.Threshold)
+	default:
+		return fmt.Sprintf("%s: %s is %d, threshold %s %g", a.Rule, subject, a.Value, a.Condition, a.Threshold)
+	}
+}
+
+// Appends the alerts to the file, one json object per line
+func AppendFile(file string, alerts []Alert) error {
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(file, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(f)
+	for _, a := range alerts {
+		if err := enc.Encode(a); err != nil {
+			_ = f.Close()
+			return err
+		}
+	}
+	return f.Close()
+}
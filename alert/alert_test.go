@@ -0,0 +1,118 @@
+package alert
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/pokt-network/relay_counter/report"
+)
+
+func threshold(v float64) *float64 {
+	return &v
+}
+
+func testReport(relays int64, nodes map[string]int64) report.Report {
+	r := report.New("timeline", report.BlockReport{MinHeight: 10, MaxHeight: 20})
+	r.TotalRelaysCompleted = relays
+	for address, nodeRelays := range nodes {
+		r.NodeReports[address] = report.NodeReport{TotalRelays: nodeRelays}
+	}
+	return r
+}
+
+func TestEvaluate(t *testing.T) {
+	// what identifies an alert, the time and message are left out
+	type raised struct {
+		rule      string
+		key       string
+		condition string
+		value     int64
+		previous  *int64
+		change    *float64
+	}
+	previousValue := func(v int64) *int64 { return &v }
+	current := testReport(800, map[string]int64{"node1": 500, "node2": 300, "node3": 0})
+	previous := testReport(1000, map[string]int64{"node1": 500, "node2": 0, "node4": 200})
+	tests := []struct {
+		name     string
+		rules    []Rule
+		previous *report.Report
+		expected []raised
+	}{
+		{
+			name:     "above",
+			rules:    []Rule{{Name: "high", Metric: MetricTotalRelays, Above: threshold(700)}},
+			expected: []raised{{rule: "high", condition: ConditionAbove, value: 800}},
+		},
+		{
+			name:  "not above",
+			rules: []Rule{{Name: "high", Metric: MetricTotalRelays, Above: threshold(800)}},
+		},
+		{
+			name:     "below",
+			rules:    []Rule{{Name: "low", Metric: MetricTotalRelays, Below: threshold(900)}},
+			expected: []raised{{rule: "low", condition: ConditionBelow, value: 800}},
+		},
+		{
+			name:     "change above",
+			rules:    []Rule{{Name: "rise", Metric: MetricTotalRelays, ChangeAbove: threshold(-25)}},
+			previous: &previous,
+			expected: []raised{{rule: "rise", condition: ConditionChangeAbove, value: 800, previous: previousValue(1000), change: threshold(-20)}},
+		},
+		{
+			name:     "change below",
+			rules:    []Rule{{Name: "drop", Metric: MetricTotalRelays, ChangeBelow: threshold(-10)}},
+			previous: &previous,
+			expected: []raised{{rule: "drop", condition: ConditionChangeBelow, value: 800, previous: previousValue(1000), change: threshold(-20)}},
+		},
+		{
+			name:  "change without a previous report",
+			rules: []Rule{{Name: "drop", Metric: MetricTotalRelays, ChangeBelow: threshold(-10), Below: threshold(900)}},
+			// only the condition on the value is checked
+			expected: []raised{{rule: "drop", condition: ConditionBelow, value: 800}},
+		},
+		{
+			name:     "change from a zero baseline",
+			rules:    []Rule{{Name: "rise", Metric: MetricNodeRelays, Key: "node2", ChangeAbove: threshold(10)}},
+			previous: &previous,
+		},
+		{
+			name:     "every key of both reports",
+			rules:    []Rule{{Name: "silent", Metric: MetricNodeRelays, Below: threshold(1)}},
+			previous: &previous,
+			expected: []raised{
+				{rule: "silent", key: "node3", condition: ConditionBelow, value: 0, previous: previousValue(0)},
+				{rule: "silent", key: "node4", condition: ConditionBelow, value: 0, previous: previousValue(200), change: threshold(-100)},
+			},
+		},
+		{
+			name:     "key in another case",
+			rules:    []Rule{{Name: "busy", Metric: MetricNodeRelays, Key: "NODE1", Above: threshold(100)}},
+			expected: []raised{{rule: "busy", key: "NODE1", condition: ConditionAbove, value: 500}},
+		},
+		{
+			name:     "both conditions of a rule",
+			rules:    []Rule{{Name: "range", Metric: MetricTotalRelays, Above: threshold(100), ChangeBelow: threshold(-10)}},
+			previous: &previous,
+			expected: []raised{
+				{rule: "range", condition: ConditionAbove, value: 800, previous: previousValue(1000), change: threshold(-20)},
+				{rule: "range", condition: ConditionChangeBelow, value: 800, previous: previousValue(1000), change: threshold(-20)},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			var got []raised
+			for _, a := range Evaluate(tt.rules, current, tt.previous) {
+				if a.Range != current.BlockReport {
+					t.Errorf("expected the range %+v, got %+v", current.BlockReport, a.Range)
+				}
+				got = append(got, raised{rule: a.Rule, key: a.Key, condition: a.Condition, value: a.Value, previous: a.Previous, change: a.Change})
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Fatalf("expected the alerts %+v, got %+v", tt.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,25 @@
+package alert
+
+import (
+	"fmt"
+)
+
+func NewMissingRuleNameError() error {
+	return fmt.Errorf("ERROR: every alert rule needs a name")
+}
+
+func NewInvalidMetricError(metric string) error {
+	return fmt.Errorf("ERROR: unrecognized alert metric: %s, valid metrics: (total_relays, challenges, minted, good_txs, bad_txs, proof_txs, filtered_relays, node_relays, app_relays, chain_relays, group_relays)", metric)
+}
+
+func NewUnexpectedKeyError(metric string) error {
+	return fmt.Errorf("ERROR: the metric %s is a total of the report, it takes no key", metric)
+}
+
+func NewMissingConditionError() error {
+	return fmt.Errorf("ERROR: the rule needs at least one of above, below, change_above or change_below")
+}
+
+func NewInvalidSeverityError(severity string) error {
+	return fmt.Errorf("ERROR: unrecognized severity: %s, valid severities: (warning, critical)", severity)
+}
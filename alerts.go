@@ -0,0 +1,96 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/pokt-network/relay_counter/alert"
+	"github.com/pokt-network/relay_counter/logging"
+	"github.com/pokt-network/relay_counter/notify"
+	"github.com/pokt-network/relay_counter/report"
+)
+
+// The rules evaluated against every report written, the alerts are also appended to File when it is set
+type AlertsConfig struct {
+	File  string       `json:"file,omitempty"`
+	Rules []alert.Rule `json:"rules"`
+}
+
+func (a AlertsConfig) Validate() (errs []error) {
+	names := make(map[string]bool)
+	for i, rule := range a.Rules {
+		name := rule.Name
+		if name == "" {
+			name = fmt.Sprintf("#%d", i+1)
+		} else if names[name] {
+			errs = append(errs, NewDuplicateAlertRuleError(name))
+		}
+		names[name] = true
+		for _, err := range rule.Validate() {
+			errs = append(errs, NewInvalidAlertRuleError(name, err))
+		}
+	}
+	return errs
+}
+
+// Whether a rule compares the report to the previous one, which is only read for those rules
+func (a *AlertsConfig) comparesReports() bool {
+	if a == nil {
+		return false
+	}
+	for _, rule := range a.Rules {
+		if rule.ChangeAbove != nil || rule.ChangeBelow != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// Evaluates the rules of the config against the report written to output, compared to the previous report when there is one.
+// The alerts are logged, appended to the alerts file and sent to the webhooks of the run. job is the scheduled job or the
+// range of a batch
+func raiseAlerts(c Config, command, job string, result report.Report, previous *report.Report, output string) []alert.Alert {
+	if c.Alerts == nil || len(c.Alerts.Rules) == 0 {
+		return nil
+	}
+	alerts := alert.Evaluate(c.Alerts.Rules, result, previous)
+	logging.Info("Evaluated the alert rules", "rules", len(c.Alerts.Rules), "alerts", len(alerts), "previous", previous != nil)
+	if len(alerts) == 0 {
+		return nil
+	}
+	for _, a := range alerts {
+		fields := []interface{}{"rule", a.Rule, "severity", a.Severity, "metric", a.Metric, "key", a.Key, "value", a.Value, "message", a.Message}
+		if a.Severity == alert.SeverityCritical {
+			logging.Error("Alert", fields...)
+		} else {
+			logging.Info("Alert", fields...)
+		}
+	}
+	if c.Alerts.File != "" {
+		if err := alert.AppendFile(c.Alerts.File, alerts); err != nil {
+			logging.Error("Could not write the alerts file", "file", c.Alerts.File, "err", err.Error())
+		}
+	}
+	if runNotifier != nil {
+		e := notify.Alerts(command, result, output, alerts)
+		e.Job = job
+		notifyRun(e)
+	}
+	return alerts
+}
+
+// Reads the report the next one is compared to, only json reports can be read back
+func readPreviousReport(file string) (*report.Report, error) {
+	if file == "" {
+		return nil, nil
+	}
+	if !strings.EqualFold(filepath.Ext(file), ".json") {
+		return nil, NewUnreadablePreviousReportError(file)
+	}
+	previous, err := report.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	return &previous, nil
+}
@@ -0,0 +1,201 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pokt-network/relay_counter/client"
+	"github.com/pokt-network/relay_counter/indexer"
+	"github.com/pokt-network/relay_counter/logging"
+	"github.com/pokt-network/relay_counter/notify"
+	"github.com/pokt-network/relay_counter/report"
+	"github.com/pokt-network/relay_counter/selector"
+)
+
+const (
+	// name of the report of the whole batch
+	BatchCombined = "combined"
+)
+
+// Several ranges reported in one run: the ranges listed, or the range of the config when there are none,
+// each one split in ranges of Split units when Split is set
+type BatchConfig struct {
+	Ranges   []BatchRange `json:"ranges,omitempty"`
+	Split    int64        `json:"split,omitempty"`
+	Combined bool         `json:"combined,omitempty"`
+}
+
+// A range of the batch, selected like the range of the config
+type BatchRange struct {
+	Name     string            `json:"name,omitempty"`
+	Selector string            `json:"selector"`
+	Timeline selector.Timeline `json:"timeline"`
+	ByBlock  selector.ByBlock  `json:"byBlock"`
+}
+
+// Checks the ranges of the batch, params are those of the config
+func (b BatchConfig) Validate(params selector.Params) (errs []error) {
+	if b.Split < 0 {
+		errs = append(errs, NewInvalidBatchSplitError(b.Split))
+	}
+	names := make(map[string]bool)
+	for i, r := range b.Ranges {
+		name := r.Name
+		if name == "" {
+			name = fmt.Sprintf("#%d", i+1)
+		} else if names[name] {
+			errs = append(errs, NewDuplicateBatchRangeError(name))
+		} else if name == BatchCombined || strings.ContainsAny(name, `/\`) {
+			errs = append(errs, NewInvalidBatchRangeNameError(name))
+		}
+		names[name] = true
+		for _, err := range r.options(params).Validate() {
+			errs = append(errs, NewInvalidBatchRangeError(name, err))
+		}
+	}
+	if b.Combined {
+		errs = append(errs, b.validateContiguous()...)
+	}
+	return errs
+}
+
+// The combined report merges the reports of the ranges, so they must follow each other without overlaps or gaps.
+// Only ranges sharing the selector, and the unit for the timeline, can be compared before they are resolved
+func (b BatchConfig) validateContiguous() (errs []error) {
+	type span struct {
+		name       string
+		start, end int64
+	}
+	if len(b.Ranges) == 0 {
+		return nil
+	}
+	first := b.Ranges[0]
+	spans := make([]span, 0, len(b.Ranges))
+	for i, r := range b.Ranges {
+		name := r.Name
+		if name == "" {
+			name = fmt.Sprintf("#%d", i+1)
+		}
+		if r.Selector != first.Selector || (r.Selector == selector.SelectorTimeline &&
+			selector.CanonicalUnit(r.Timeline.Unit) != selector.CanonicalUnit(first.Timeline.Unit)) {
+			return []error{NewMixedCombinedBatchError()}
+		}
+		switch r.Selector {
+		case selector.SelectorTimeline:
+			spans = append(spans, span{name: name, start: r.Timeline.Start, end: r.Timeline.End})
+		case selector.SelectorByBlock:
+			spans = append(spans, span{name: name, start: r.ByBlock.Start, end: r.ByBlock.End})
+		}
+	}
+	sort.SliceStable(spans, func(i, j int) bool { return spans[i].start < spans[j].start })
+	for i := 1; i < len(spans); i++ {
+		prev, cur := spans[i-1], spans[i]
+		if cur.start < prev.end {
+			errs = append(errs, NewOverlappingBatchRangesError(prev.name, cur.name))
+		} else if cur.start > prev.end {
+			errs = append(errs, NewNonContiguousBatchRangesError(prev.name, cur.name))
+		}
+	}
+	return errs
+}
+
+func (r BatchRange) options(params selector.Params) selector.Options {
+	return selector.Options{Selector: r.Selector, Timeline: r.Timeline, ByBlock: r.ByBlock, Params: params}
+}
+
+// The ranges of the batch, the split ranges are named after their heights
+func (c Config) BatchRanges() (ranges []indexer.BatchRange) {
+	if len(c.Batch.Ranges) == 0 {
+		ranges = append(ranges, indexer.BatchRange{Range: c.SelectorOptions()})
+	}
+	for _, r := range c.Batch.Ranges {
+		ranges = append(ranges, indexer.BatchRange{Name: r.Name, Range: r.options(c.Params)})
+	}
+	if c.Batch.Split == 0 {
+		return ranges
+	}
+	var split []indexer.BatchRange
+	for _, r := range ranges {
+		for _, part := range r.Range.Split(c.Batch.Split) {
+			split = append(split, indexer.BatchRange{Range: part})
+		}
+	}
+	return split
+}
+
+// Generates the reports of the batch and exits, with the same exit codes as a single report
+// Every range is compared by the alert rules to the range before it, the first one to previous
+func runBatch(ctx context.Context, pocket *client.Client, c Config, opts indexer.Options, resultFile, format string, partial bool, previous *report.Report) {
+	ranges := c.BatchRanges()
+	logging.Info("Generating the batch", "ranges", len(ranges), "combined", c.Batch.Combined)
+	results, err := indexer.GenerateBatch(ctx, pocket, opts, ranges)
+	saveRecording()
+	incompleteErr, incomplete := err.(*report.IncompleteError)
+	if err != nil && !incomplete {
+		fatal(err)
+	}
+	if ctx.Err() != nil && len(results) == 0 {
+		logging.Info("Interrupted before any heights were retrieved")
+		os.Exit(ExitCodeInterrupted)
+	}
+	if incomplete && ctx.Err() == nil {
+		logging.Error(incompleteErr.Error())
+		for _, failed := range incompleteErr.FailedHeights {
+			logging.Error("Failed height", "height", failed.Height, "phase", failed.Phase, "err", failed.Message)
+		}
+		if !partial {
+			fatal(err)
+		}
+	}
+	names := make([]string, 0, len(results))
+	reports := make([]report.Report, 0, len(results))
+	for i, r := range results {
+		file := batchFile(resultFile, r.Name, format)
+		logging.Info("Writing the report of the range", "range", r.Name, "min_height", r.Report.BlockReport.MinHeight,
+			"max_height", r.Report.BlockReport.MaxHeight, "incomplete", r.Report.Incomplete, "file", file)
+		writeReport(r.Report, c, file, format)
+		e := notify.Success(CommandReport, r.Report, file)
+		if r.Report.Incomplete {
+			e = notify.Incomplete(CommandReport, r.Report, file, &report.IncompleteError{FailedHeights: r.Report.FailedHeights})
+		}
+		e.Job = r.Name
+		notifyRun(e)
+		raiseAlerts(c, CommandReport, r.Name, r.Report, previous, file)
+		previous = &results[i].Report
+		names = append(names, r.Name)
+		reports = append(reports, r.Report)
+	}
+	if c.Batch.Combined {
+		combined, err := report.MergeNamed(names, reports)
+		if err != nil {
+			fatal(err)
+		}
+		combined.BlockSelector = indexer.SelectorBatch
+		// the ranges share the config
+		combined.Metadata.ConfigHash = opts.ConfigHash
+		file := batchFile(resultFile, BatchCombined, format)
+		logging.Info("Writing the combined report", "min_height", combined.BlockReport.MinHeight,
+			"max_height", combined.BlockReport.MaxHeight, "file", file)
+		writeReport(combined, c, file, format)
+	}
+	switch {
+	case ctx.Err() != nil:
+		os.Exit(ExitCodeInterrupted)
+	case incomplete:
+		os.Exit(ExitCodeIncomplete)
+	}
+	logging.Info("Done")
+}
+
+// The results file of a range, named after the range. The ranges share the sqlite database
+func batchFile(resultFile, name, format string) string {
+	if strings.ToLower(format) == FormatSQLite {
+		return resultFile
+	}
+	ext := filepath.Ext(resultFile)
+	return strings.TrimSuffix(resultFile, ext) + "-" + name + ext
+}
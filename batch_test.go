@@ -0,0 +1,83 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/pokt-network/relay_counter/selector"
+)
+
+func byBlockRange(name string, start, end int64) BatchRange {
+	return BatchRange{Name: name, Selector: selector.SelectorByBlock, ByBlock: selector.ByBlock{Start: start, End: end}}
+}
+
+func timelineRange(name string, start, end int64, unit string) BatchRange {
+	return BatchRange{Name: name, Selector: selector.SelectorTimeline, Timeline: selector.Timeline{Start: start, End: end, Unit: unit}}
+}
+
+// A combined batch is refused before any request when its ranges can't be merged
+func TestBatchConfigValidate(t *testing.T) {
+	params := selector.Params{BlocksPerSession: 4, AppxBlockTimeInMinutes: 15}
+	tests := []struct {
+		name  string
+		batch BatchConfig
+		errs  []error
+	}{
+		{
+			name:  "contiguous heights in any order",
+			batch: BatchConfig{Combined: true, Ranges: []BatchRange{byBlockRange("b", 20, 30), byBlockRange("a", 10, 20), byBlockRange("c", 30, 40)}},
+		},
+		{
+			name:  "overlapping heights",
+			batch: BatchConfig{Combined: true, Ranges: []BatchRange{byBlockRange("a", 10, 21), byBlockRange("b", 20, 30)}},
+			errs:  []error{NewOverlappingBatchRangesError("a", "b")},
+		},
+		{
+			name:  "a gap between the heights",
+			batch: BatchConfig{Combined: true, Ranges: []BatchRange{byBlockRange("", 10, 20), byBlockRange("", 21, 30)}},
+			errs:  []error{NewNonContiguousBatchRangesError("#1", "#2")},
+		},
+		{
+			name:  "a gap without the combined report",
+			batch: BatchConfig{Ranges: []BatchRange{byBlockRange("a", 10, 20), byBlockRange("b", 21, 30)}},
+		},
+		{
+			name:  "contiguous timelines with aliased units",
+			batch: BatchConfig{Combined: true, Ranges: []BatchRange{timelineRange("a", -4, -2, "hours"), timelineRange("b", -2, 0, "h")}},
+		},
+		{
+			name:  "overlapping timelines",
+			batch: BatchConfig{Combined: true, Ranges: []BatchRange{timelineRange("a", -4, -1, "days"), timelineRange("b", -2, 0, "days")}},
+			errs:  []error{NewOverlappingBatchRangesError("a", "b")},
+		},
+		{
+			name:  "timelines in different units",
+			batch: BatchConfig{Combined: true, Ranges: []BatchRange{timelineRange("a", -4, -2, "hours"), timelineRange("b", -2, 0, "days")}},
+			errs:  []error{NewMixedCombinedBatchError()},
+		},
+		{
+			name:  "different selectors",
+			batch: BatchConfig{Combined: true, Ranges: []BatchRange{byBlockRange("a", 10, 20), timelineRange("b", -2, 0, "days")}},
+			errs:  []error{NewMixedCombinedBatchError()},
+		},
+		{
+			name:  "the range of the config",
+			batch: BatchConfig{Combined: true, Split: 4},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			var got, expected []string
+			for _, err := range tt.batch.Validate(params) {
+				got = append(got, err.Error())
+			}
+			for _, err := range tt.errs {
+				expected = append(expected, err.Error())
+			}
+			if !reflect.DeepEqual(got, expected) {
+				t.Fatalf("expected the errors %v, got %v", expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,185 @@
+package client
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// The requests made to a node and its responses, recorded by a Recorder and served back by a Replayer.
+// Saved as gzipped JSON
+type Archive struct {
+	Endpoint     string        `json:"endpoint"`
+	RecordedAt   time.Time     `json:"recorded_at"`
+	Interactions []Interaction `json:"interactions"`
+}
+
+// A request and its response, in the order they were made
+type Interaction struct {
+	Method   string `json:"method"`
+	Path     string `json:"path"`
+	Request  string `json:"request,omitempty"`
+	Status   int    `json:"status"`
+	Response string `json:"response"`
+}
+
+func (i Interaction) key() string {
+	return i.Method + " " + i.Path + " " + i.Request
+}
+
+func LoadArchive(file string) (a Archive, err error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return a, err
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return a, NewInvalidArchiveError(file, err)
+	}
+	defer gz.Close()
+	if err := json.NewDecoder(gz).Decode(&a); err != nil {
+		return a, NewInvalidArchiveError(file, err)
+	}
+	return a, nil
+}
+
+func (a Archive) Save(file string) error {
+	buf := &bytes.Buffer{}
+	gz := gzip.NewWriter(buf)
+	if err := json.NewEncoder(gz).Encode(a); err != nil {
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(file, buf.Bytes(), 0644)
+}
+
+// An http.RoundTripper recording every request that gets a response, failed ones included, so a replay fails the same way
+type Recorder struct {
+	next    http.RoundTripper
+	mu      sync.Mutex
+	archive Archive
+}
+
+// Records the requests sent through next, http.DefaultTransport if nil
+func NewRecorder(endpoint string, next http.RoundTripper) *Recorder {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &Recorder{
+		next:    next,
+		archive: Archive{Endpoint: endpoint, RecordedAt: time.Now().UTC()},
+	}
+}
+
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	reqBz, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+	res, err := r.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	resBz, err := ioutil.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	res.Body = ioutil.NopCloser(bytes.NewReader(resBz))
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.archive.Interactions = append(r.archive.Interactions, Interaction{
+		Method:   req.Method,
+		Path:     req.URL.Path,
+		Request:  string(reqBz),
+		Status:   res.StatusCode,
+		Response: string(resBz),
+	})
+	return res, nil
+}
+
+// The interactions recorded so far
+func (r *Recorder) Archive() Archive {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	a := r.archive
+	a.Interactions = append([]Interaction(nil), r.archive.Interactions...)
+	return a
+}
+
+func (r *Recorder) Save(file string) error {
+	return r.Archive().Save(file)
+}
+
+// An http.RoundTripper answering from an archive without reaching any node.
+// The responses to the same request are served in the recorded order, the last one is repeated once they run out
+type Replayer struct {
+	mu        sync.Mutex
+	responses map[string][]Interaction
+	served    map[string]int
+}
+
+func NewReplayer(a Archive) *Replayer {
+	r := &Replayer{
+		responses: make(map[string][]Interaction),
+		served:    make(map[string]int),
+	}
+	for _, i := range a.Interactions {
+		r.responses[i.key()] = append(r.responses[i.key()], i)
+	}
+	return r
+}
+
+func (r *Replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	reqBz, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+	key := Interaction{Method: req.Method, Path: req.URL.Path, Request: string(reqBz)}.key()
+	r.mu.Lock()
+	responses := r.responses[key]
+	if len(responses) == 0 {
+		r.mu.Unlock()
+		return nil, &NotRecordedError{Method: req.Method, Path: req.URL.Path, Request: string(reqBz)}
+	}
+	i := r.served[key]
+	if i >= len(responses) {
+		i = len(responses) - 1
+	}
+	r.served[key]++
+	r.mu.Unlock()
+	return &http.Response{
+		Status:        http.StatusText(responses[i].Status),
+		StatusCode:    responses[i].Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": []string{"application/json"}},
+		Body:          ioutil.NopCloser(strings.NewReader(responses[i].Response)),
+		ContentLength: int64(len(responses[i].Response)),
+		Request:       req,
+	}, nil
+}
+
+// Reads the body of the request and puts it back for the next transport
+func readRequestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil {
+		return nil, nil
+	}
+	bz, err := ioutil.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	req.Body = ioutil.NopCloser(bytes.NewReader(bz))
+	return bz, nil
+}
@@ -0,0 +1,233 @@
+// Package client retrieves the chain data of a pocket-core node over its RPC.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pokt-network/pocket-core/app/cmd/rpc"
+	pcTypes "github.com/pokt-network/pocket-core/x/pocketcore/types"
+	"github.com/pokt-network/relay_counter/logging"
+	coretypes "github.com/tendermint/tendermint/rpc/core/types"
+)
+
+const (
+	BlockTxsPath = "/query/blocktxs"
+	ClaimsPath   = "/query/nodeclaims"
+	HeightPath   = "/query/height"
+	BlockPath    = "/query/block"
+	SupplyPath   = "/query/supply"
+)
+
+type PaginatedHeightParams struct {
+	Height  int64  `json:"height"`
+	Page    int    `json:"page,omitempty"`
+	PerPage int    `json:"per_page,omitempty"`
+	Prove   bool   `json:"prove,omitempty"`
+	Sort    string `json:"order,omitempty"`
+}
+
+type ClaimsRPCResponse struct {
+	Claims []pcTypes.MsgClaim `json:"result"`
+	Total  int                `json:"total_pages"`
+	Page   int                `json:"page"`
+}
+
+type HeightRPCResponse struct {
+	Height int64 `json:"height"`
+}
+
+type SupplyRPCResponse struct {
+	Total string `json:"total"`
+}
+
+// The RPC client of a pocket-core node, the endpoint is the version endpoint (ending in /v1)
+type Client struct {
+	endpoint   string
+	retries    int
+	httpClient *http.Client
+}
+
+type Option func(*Client)
+
+// Sets how many times a failed request is retried by Retry, 0 by default
+func WithRetries(retries int) Option {
+	return func(c *Client) {
+		c.retries = retries
+	}
+}
+
+// Sets the http client the requests are sent with, http.DefaultClient by default
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+func New(endpoint string, opts ...Option) *Client {
+	c := &Client{
+		endpoint:   endpoint,
+		httpClient: http.DefaultClient,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+func (c *Client) Endpoint() string {
+	return c.endpoint
+}
+
+// Checks the endpoint is a reachable pocket-core version endpoint
+func (c *Client) Test(ctx context.Context) error {
+	if !strings.HasSuffix(c.endpoint, "v1") {
+		return NewInvalidEndpointError(c.endpoint)
+	}
+	req, err := http.NewRequestWithContext(ctx, "GET", c.endpoint, nil)
+	if err != nil {
+		return err
+	}
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != 200 {
+		return NewHTTPStatusCode(res.StatusCode, "")
+	}
+	return nil
+}
+
+// Calls fn until it succeeds or fails the configured retries more times, sleeping in between.
+// Decoding errors and requests missing from a replayed archive are not retried, and the retries stop with the error of ctx once it is cancelled
+func (c *Client) Retry(ctx context.Context, description string, sleep time.Duration, fn func() error) error {
+	for count := 0; ; count++ {
+		err := fn()
+		if err == nil {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if _, ok := err.(*TxDecodeError); ok {
+			return err
+		}
+		var notRecorded *NotRecordedError
+		if errors.As(err, &notRecorded) {
+			return notRecorded
+		}
+		if count >= c.retries {
+			return NewRetriesExhaustedError(c.retries, description, err)
+		}
+		logging.Info("RPC failure, retrying", "request", description, "err", err.Error(), "retry", count+1, "retries", c.retries)
+		// arbitrary sleep to retry
+		timer := time.NewTimer(sleep)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// Posts the params to the path and returns the body of the response
+func (c *Client) post(ctx context.Context, path string, params interface{}) ([]byte, error) {
+	var body io.Reader
+	if params != nil {
+		r, err := json.Marshal(params)
+		if err != nil {
+			return nil, err
+		}
+		body = bytes.NewBuffer(r)
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", c.endpoint+path, body)
+	if err != nil {
+		return nil, err
+	}
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	bodyBz, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode != 200 {
+		return nil, NewHTTPStatusCode(res.StatusCode, string(bodyBz))
+	}
+	return bodyBz, nil
+}
+
+func (c *Client) GetBlockTx(ctx context.Context, height int64, page int) (result rpc.RPCResultTxSearch, err error) {
+	bodyBz, err := c.post(ctx, BlockTxsPath, PaginatedHeightParams{
+		Height:  height,
+		PerPage: 1000,
+		Page:    page,
+	})
+	if err != nil {
+		return result, err
+	}
+	rts := &coretypes.ResultTxSearch{}
+	err = json.Unmarshal(bodyBz, &rts)
+	if err != nil {
+		return result, err
+	}
+	return ResultTxSearchToRPC(rts)
+}
+
+func (c *Client) GetClaims(ctx context.Context, height int64) (result []pcTypes.MsgClaim, err error) {
+	bodyBz, err := c.post(ctx, ClaimsPath, PaginatedHeightParams{
+		Height:  height,
+		PerPage: 10000, // TODO will fail if over 10K claims in 1 block
+	})
+	if err != nil {
+		return nil, err
+	}
+	state := ClaimsRPCResponse{}
+	err = json.Unmarshal(bodyBz, &state)
+	return state.Claims, err
+}
+
+func (c *Client) GetLatestHeight(ctx context.Context) (int64, error) {
+	bodyBz, err := c.post(ctx, HeightPath, nil)
+	if err != nil {
+		return 0, err
+	}
+	height := HeightRPCResponse{}
+	err = json.Unmarshal(bodyBz, &height)
+	return height.Height, err
+}
+
+func (c *Client) GetBlock(ctx context.Context, height int64) (block *coretypes.ResultBlock, err error) {
+	bodyBz, err := c.post(ctx, BlockPath, PaginatedHeightParams{Height: height})
+	if err != nil {
+		return nil, err
+	}
+	err = cdc.UnmarshalJSON(bodyBz, &block)
+	return
+}
+
+func (c *Client) GetSupply(ctx context.Context, height int64) (supply int, err error) {
+	bodyBz, err := c.post(ctx, SupplyPath, PaginatedHeightParams{Height: height})
+	if err != nil {
+		return 0, err
+	}
+	s := SupplyRPCResponse{}
+	err = cdc.UnmarshalJSON(bodyBz, &s)
+	if err != nil {
+		return 0, err
+	}
+	supply, err = strconv.Atoi(s.Total)
+	return
+}
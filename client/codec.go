@@ -0,0 +1,111 @@
+package client
+
+import (
+	"github.com/pokt-network/pocket-core/app/cmd/rpc"
+	"github.com/pokt-network/pocket-core/codec"
+	types3 "github.com/pokt-network/pocket-core/codec/types"
+	"github.com/pokt-network/pocket-core/crypto"
+	pc "github.com/pokt-network/pocket-core/types"
+	appTypes "github.com/pokt-network/pocket-core/x/apps/types"
+	"github.com/pokt-network/pocket-core/x/auth"
+	authTypes "github.com/pokt-network/pocket-core/x/auth"
+	"github.com/pokt-network/pocket-core/x/auth/types"
+	govTypes "github.com/pokt-network/pocket-core/x/gov"
+	nodeTypes "github.com/pokt-network/pocket-core/x/nodes/types"
+	pcTypes "github.com/pokt-network/pocket-core/x/pocketcore/types"
+	cryptoamino "github.com/tendermint/tendermint/crypto/encoding/amino"
+	coretypes "github.com/tendermint/tendermint/rpc/core/types"
+)
+
+var (
+	cdc = codec.NewCodec(types3.NewInterfaceRegistry())
+)
+
+func init() {
+	cdc.SetUpgradeOverride(false)
+	pc.RegisterCodec(cdc)
+	pcTypes.RegisterCodec(cdc)
+	authTypes.RegisterCodec(cdc)
+	nodeTypes.RegisterCodec(cdc)
+	appTypes.RegisterCodec(cdc)
+	govTypes.RegisterCodec(cdc)
+	crypto.RegisterAmino(cdc.AminoCodec().Amino)
+	cryptoamino.RegisterAmino(cdc.AminoCodec().Amino)
+	codec.RegisterEvidences(cdc.AminoCodec(), cdc.ProtoCodec())
+}
+
+// Encodes the tx the way the node does, the inverse of UnmarshalTx
+func MarshalTx(tx types.StdTx, height int64) ([]byte, error) {
+	return auth.DefaultTxEncoder(cdc)(tx, height)
+}
+
+// Encodes o in the amino json of the node responses
+func MarshalJSON(o interface{}) ([]byte, error) {
+	return cdc.MarshalJSON(o)
+}
+
+func ResultTxSearchToRPC(res *coretypes.ResultTxSearch) (rpc.RPCResultTxSearch, error) {
+	if res == nil {
+		return rpc.RPCResultTxSearch{}, nil
+	}
+	rpcTxSearch := rpc.RPCResultTxSearch{
+		Txs:        make([]*rpc.RPCResultTx, 0, res.TotalCount),
+		TotalCount: res.TotalCount,
+	}
+	for _, result := range res.Txs {
+		tx, err := ResultTxToRPC(result)
+		if err != nil {
+			return rpcTxSearch, err
+		}
+		rpcTxSearch.Txs = append(rpcTxSearch.Txs, tx)
+	}
+	return rpcTxSearch, nil
+}
+
+func ResultTxToRPC(res *coretypes.ResultTx) (*rpc.RPCResultTx, error) {
+	if res == nil {
+		return nil, nil
+	}
+	tx, err := UnmarshalTx(res.Tx, res.Height)
+	if err != nil {
+		return nil, err
+	}
+	//if app.GlobalConfig.PocketConfig.DisableTxEvents {
+	res.TxResult.Events = nil
+	//}
+	rpcDeliverTx := rpc.RPCResponseDeliverTx{
+		Code:        res.TxResult.Code,
+		Data:        res.TxResult.Data,
+		Log:         res.TxResult.Log,
+		Info:        res.TxResult.Info,
+		Events:      res.TxResult.Events,
+		Codespace:   res.TxResult.Codespace,
+		Signer:      res.TxResult.Signer,
+		Recipient:   res.TxResult.Recipient,
+		MessageType: res.TxResult.MessageType,
+	}
+	rpcStdTx := rpc.RPCStdTx(tx)
+	r := &rpc.RPCResultTx{
+		Hash:     res.Hash,
+		Height:   res.Height,
+		Index:    res.Index,
+		TxResult: rpcDeliverTx,
+		Tx:       res.Tx,
+		Proof:    res.Proof,
+		StdTx:    rpcStdTx,
+	}
+	return r, nil
+}
+
+func UnmarshalTx(txBytes []byte, height int64) (types.StdTx, error) {
+	defaultTxDecoder := auth.DefaultTxDecoder(cdc)
+	tx, err := defaultTxDecoder(txBytes, height)
+	if err != nil {
+		return types.StdTx{}, &TxDecodeError{Height: height, Err: err}
+	}
+	stdTx, ok := tx.(auth.StdTx)
+	if !ok {
+		return types.StdTx{}, &TxDecodeError{Height: height, Err: NewStdTxInterfaceError()}
+	}
+	return stdTx, nil
+}
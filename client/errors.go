@@ -0,0 +1,50 @@
+package client
+
+import (
+	"fmt"
+)
+
+const (
+	HTTPStatusCodeError = "status code non 200"
+)
+
+func NewHTTPStatusCode(code int, body string) error {
+	return fmt.Errorf(HTTPStatusCodeError+": %d: With body %s", code, body)
+}
+
+func NewInvalidEndpointError(endpoint string) error {
+	return fmt.Errorf("ERROR: endpoint must be pocket-core version endpoint, got %s", endpoint)
+}
+
+func NewStdTxInterfaceError() error {
+	return fmt.Errorf("ERROR: unable to convert interface to StdTx")
+}
+
+func NewRetriesExhaustedError(retries int, description string, err error) error {
+	return fmt.Errorf("ERROR: after %d retries, unable to get %s with error: %s", retries, description, err.Error())
+}
+
+// A transaction that can't be decoded, retrying won't help
+type TxDecodeError struct {
+	Height int64
+	Err    error
+}
+
+func (e *TxDecodeError) Error() string {
+	return fmt.Sprintf("ERROR: could not decode transaction at height %d: %s", e.Height, e.Err.Error())
+}
+
+func NewInvalidArchiveError(file string, err error) error {
+	return fmt.Errorf("ERROR: unable to read archive %s: %s", file, err.Error())
+}
+
+// A request missing from the archive being replayed, retrying won't help
+type NotRecordedError struct {
+	Method  string
+	Path    string
+	Request string
+}
+
+func (e *NotRecordedError) Error() string {
+	return fmt.Sprintf("ERROR: %s %s with body %s was not recorded in the archive", e.Method, e.Path, e.Request)
+}
@@ -4,153 +4,265 @@ import (
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
-	"log"
-	"math"
-	"net/http"
+	"net/url"
 	"os"
+	"path/filepath"
 	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/pokt-network/relay_counter/client"
+	"github.com/pokt-network/relay_counter/indexer"
+	"github.com/pokt-network/relay_counter/logging"
+	"github.com/pokt-network/relay_counter/report"
+	"github.com/pokt-network/relay_counter/selector"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	FormatJSON     = "json"
+	FormatCSV      = "csv"
+	FormatSQLite   = "sqlite"
+	FormatMarkdown = "markdown"
+	FormatHTML     = "html"
 )
 
 type Config struct {
-	Selector  string   `json:"selector"`
-	Timeline  Timeline `json:"timeline"`
-	ByBlock   ByBlock  `json:"byBlock"`
-	Endpoint  string   `json:"endpoint"`
-	HTTPRetry int      `json:"http_retry"`
-	Params    Params   `json:"params"`
+	Profile   string                  `json:"profile,omitempty"`
+	Selector  string                  `json:"selector"`
+	Timeline  selector.Timeline       `json:"timeline"`
+	ByBlock   selector.ByBlock        `json:"byBlock"`
+	Endpoint  string                  `json:"endpoint"`
+	HTTPRetry int                     `json:"http_retry"`
+	Params    selector.Params         `json:"params"`
+	Filters   report.Filters          `json:"filters"`
+	Groups    map[string]report.Group `json:"groups"`
+	Batch     *BatchConfig            `json:"batch,omitempty"`
+	Schedule  *ScheduleConfig         `json:"schedule,omitempty"`
+	Notify    *NotifyConfig           `json:"notify,omitempty"`
+	Alerts    *AlertsConfig           `json:"alerts,omitempty"`
 }
 
-type TimelineJSON Timeline
-
-type Params struct {
-	AppxBlockTimeInMinutes int64 `json:"approx_block_time_in_min"`
-	BlocksPerSession       int64 `json:"blocks_per_session"`
+// The RPC client of the configured endpoint, the options are applied after the configured retries
+func (c Config) Client(opts ...client.Option) *client.Client {
+	return client.New(c.Endpoint, append([]client.Option{client.WithRetries(c.HTTPRetry)}, opts...)...)
 }
 
-func (t *Timeline) UnmarshalJSON(data []byte) error {
-	tlj := TimelineJSON{}
-	err := json.Unmarshal(data, &tlj)
-	if err != nil {
-		return err
-	}
-	t.Unit = tlj.Unit
-	t.End = int64(math.Abs(float64(tlj.End)) * -1)
-	t.Start = int64(math.Abs(float64(tlj.Start)) * -1)
-	if t.Start > t.End {
-		return NewInvalidStartEndError(t.Start*-1, t.End*-1, t.Unit)
-	}
-	switch strings.ToLower(t.Unit) {
-	case UnitMinutes, UnitMinute, UnitMin, UnitM:
-		// all good
-	case UnitHours, UnitHour, UnitHr, UnitH:
-		// all good
-	case UnitDays, UnitDay, UnitD:
-		// all good
-	case UnitWeeks, UnitWeek, UnitW:
-		// all good
-	case UnitBlocks, UnitBlock, UnitB:
-		// all good
-	case UnitSessions, UnitSession, UnitS:
-		// all good
-	default:
-		return NewInvalidUnitError(t.Unit)
+func (c Config) SelectorOptions() selector.Options {
+	return selector.Options{
+		Selector: c.Selector,
+		Timeline: c.Timeline,
+		ByBlock:  c.ByBlock,
+		Params:   c.Params,
 	}
-	return nil
 }
 
-// Gets the conf in the config file
-func getConfig(file string) Config {
-	fBz, err := ioutil.ReadFile(file)
-	if err != nil {
-		log.Fatalf(err.Error())
+// Checks every field of the config without reaching the node, so all the problems can be fixed at once
+func (c Config) Validate() (errs []error) {
+	errs = append(errs, c.SelectorOptions().Validate()...)
+	if c.Endpoint == "" {
+		errs = append(errs, NewMissingEndpointError())
+	} else if u, err := url.Parse(c.Endpoint); err != nil || u.Scheme == "" || u.Host == "" {
+		errs = append(errs, NewInvalidEndpointURLError(c.Endpoint))
+	} else if !strings.HasSuffix(c.Endpoint, "v1") {
+		errs = append(errs, client.NewInvalidEndpointError(c.Endpoint))
 	}
-	c := Config{}
-	err = json.Unmarshal(fBz, &c)
-	if err != nil {
-		log.Fatalf(err.Error())
+	if c.HTTPRetry < 0 {
+		errs = append(errs, NewInvalidHTTPRetryError(c.HTTPRetry))
+	}
+	if _, err := report.NewFilter(c.Filters); err != nil {
+		errs = append(errs, err)
+	}
+	if _, err := report.NewGroups(c.Groups); err != nil {
+		errs = append(errs, err)
 	}
-	return c
+	if c.Batch != nil {
+		errs = append(errs, c.Batch.Validate(c.Params)...)
+	}
+	if c.Schedule != nil {
+		errs = append(errs, c.Schedule.Validate(c.Params)...)
+	}
+	if c.Notify != nil {
+		errs = append(errs, c.Notify.Validate()...)
+	}
+	if c.Alerts != nil {
+		errs = append(errs, c.Alerts.Validate()...)
+	}
+	return errs
 }
 
-func testEndpoint(endpoint string) error {
-	r, err := http.NewRequest("GET", endpoint, nil)
+// Builds the filter and groups of the config into the options of the indexer
+func (c Config) IndexerOptions() (opts indexer.Options, err error) {
+	filter, err := report.NewFilter(c.Filters)
 	if err != nil {
-		return err
+		return opts, err
 	}
-	c := http.Client{}
-	res, err := c.Do(r)
+	groups, err := report.NewGroups(c.Groups)
 	if err != nil {
-		return err
-	}
-	if endpoint[len(endpoint)-2:] != "v1" {
-		return fmt.Errorf("endpoint must be pocket-core version endpoint")
+		return opts, err
 	}
-	if res.StatusCode != 200 {
-		return NewHTTPStatusCode(res.StatusCode, "")
+	return indexer.Options{
+		Range:      c.SelectorOptions(),
+		Filter:     filter,
+		Groups:     groups,
+		ConfigHash: report.ConfigHash(c),
+		Profile:    c.Profile,
+	}, nil
+}
+
+// The config before the file, the environment variables and the flags are applied
+func DefaultConfig() Config {
+	return Config{
+		Selector:  selector.SelectorTimeline,
+		Timeline:  selector.Timeline{Start: -1, End: 0, Unit: selector.UnitDay},
+		Endpoint:  "http://localhost:8081/v1",
+		HTTPRetry: 3,
+		Params: selector.Params{
+			AppxBlockTimeInMinutes: 15,
+			BlocksPerSession:       4,
+		},
 	}
-	return nil
 }
 
-func writeResultFile(result Report, file string) {
-	j, err := json.MarshalIndent(result, "", "    ")
+// Reads the config file into the generic map of its keys, the format is chosen by the extension
+func readConfigFile(file string) (map[string]interface{}, error) {
+	fBz, err := ioutil.ReadFile(file)
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
+	}
+	m := make(map[string]interface{})
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".json":
+		err = json.Unmarshal(fBz, &m)
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(fBz, &m)
+	case ".toml":
+		_, err = toml.Decode(string(fBz), &m)
+	default:
+		return nil, NewUnsupportedConfigFileError(file)
 	}
-	err = ioutil.WriteFile(file, j, os.ModePerm)
 	if err != nil {
-		log.Println("ERROR : COULD NOT WRITE REPORT FILE: ", err.Error(), "\nPRINTING TO SCREEN")
-		fmt.Println(string(j))
+		return nil, NewInvalidConfigFileError(file, err)
 	}
+	return m, nil
 }
 
-func overrideConfig(
-	c Config,
-	selector string,
-	timelineStart int64, timelineEnd int64, timelineUnit string,
-	startBlock int64, endBlock int64,
-	endpoint string, httpRetry int,
-	blocksPerSession int64, blockTimeInMin int64,
-) Config {
-	log.Println("Processing command line overrides")
-	if selector != "" {
-		c.Selector = selector
+func toConfigMap(c Config) (map[string]interface{}, error) {
+	bz, err := json.Marshal(c)
+	if err != nil {
+		return nil, err
 	}
+	m := make(map[string]interface{})
+	return m, json.Unmarshal(bz, &m)
+}
 
-	if timelineStart != -99999 {
-		c.Timeline.Start = timelineStart
+// Sets the value at the dotted path, creating the maps on the way
+func setConfigKey(m map[string]interface{}, path string, value interface{}) {
+	keys := strings.Split(path, ".")
+	for _, key := range keys[:len(keys)-1] {
+		next, ok := m[key].(map[string]interface{})
+		if !ok {
+			next = make(map[string]interface{})
+			m[key] = next
+		}
+		m = next
 	}
+	m[keys[len(keys)-1]] = value
+}
 
-	if timelineEnd != -99999 {
-		c.Timeline.End = timelineEnd
+// Merges src into dst, the maps found in both are merged key by key and the other values of src replace those of dst
+func mergeConfigMaps(dst, src map[string]interface{}) {
+	for key, value := range src {
+		srcMap, srcIsMap := value.(map[string]interface{})
+		dstMap, dstIsMap := dst[key].(map[string]interface{})
+		if srcIsMap {
+			// copied, so the later layers do not modify the maps of the earlier ones
+			if !dstIsMap {
+				dstMap = make(map[string]interface{})
+				dst[key] = dstMap
+			}
+			mergeConfigMaps(dstMap, srcMap)
+			continue
+		}
+		dst[key] = value
 	}
+}
 
-	if timelineUnit != "" {
-		c.Timeline.Unit = timelineUnit
+// Decodes the layers into a config, each layer overriding the keys it sets in the previous ones
+func mergeConfig(layers ...map[string]interface{}) (Config, error) {
+	merged := make(map[string]interface{})
+	for _, layer := range layers {
+		mergeConfigMaps(merged, layer)
 	}
-
-	if startBlock != -99999 {
-		c.ByBlock.Start = startBlock
+	bz, err := json.Marshal(merged)
+	if err != nil {
+		return Config{}, err
 	}
-
-	if endBlock != -99999 {
-		c.ByBlock.End = endBlock
+	c := Config{}
+	if err := json.Unmarshal(bz, &c); err != nil {
+		return Config{}, NewInvalidConfigError(err)
 	}
+	return c, nil
+}
 
-	if endpoint != "" {
-		c.Endpoint = endpoint
+func writeResultFile(result report.Report, file string) {
+	j, err := json.MarshalIndent(result, "", "    ")
+	if err != nil {
+		fatal(err)
 	}
-
-	if httpRetry != -1 {
-		c.HTTPRetry = httpRetry
+	err = ioutil.WriteFile(file, j, os.ModePerm)
+	if err != nil {
+		logging.Error("Could not write the report file, printing it to the screen", "file", file, "err", err.Error())
+		fmt.Println(string(j))
 	}
+}
 
-	if blocksPerSession != -1 {
-		c.Params.BlocksPerSession = blocksPerSession
+func isValidFormat(format string) bool {
+	switch strings.ToLower(format) {
+	case FormatJSON, FormatCSV, FormatSQLite, FormatMarkdown, FormatHTML:
+		return true
 	}
+	return false
+}
 
-	if blockTimeInMin != -1 {
-		c.Params.AppxBlockTimeInMinutes = blockTimeInMin
+// Writes the report in the given output format
+func writeReport(result report.Report, config Config, file string, format string) {
+	switch strings.ToLower(format) {
+	case FormatJSON:
+		writeResultFile(result, file)
+	case FormatCSV:
+		files, err := writeCSVFiles(result, file)
+		if err != nil {
+			logging.Error("Could not write the CSV files, falling back to JSON", "err", err.Error())
+			writeResultFile(result, file)
+			return
+		}
+		logging.Info("CSV files written", "files", strings.Join(files, ", "))
+	case FormatSQLite:
+		runID, err := writeSQLiteFile(result, config, file)
+		if err != nil {
+			logging.Error("Could not write to the sqlite database, falling back to JSON", "err", err.Error())
+			writeResultFile(result, strings.TrimSuffix(file, filepath.Ext(file))+".json")
+			return
+		}
+		logging.Info("Report stored in the sqlite database", "run", runID, "file", file)
+	case FormatMarkdown, FormatHTML:
+		// the summary is written alongside the json report
+		writeResultFile(result, file)
+		summaryFile, err := writeSummaryFile(result, file, strings.ToLower(format))
+		if err != nil {
+			logging.Error("Could not write the summary file", "err", err.Error())
+			return
+		}
+		logging.Info("Summary written", "file", summaryFile)
+	default:
+		logging.Error("Falling back to JSON", "err", NewInvalidFormatError(format).Error())
+		writeResultFile(result, file)
 	}
+}
 
-	return c
+func splitList(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, ",")
 }
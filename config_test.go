@@ -0,0 +1,149 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/pokt-network/relay_counter/report"
+	"github.com/pokt-network/relay_counter/selector"
+)
+
+// The same config in every supported format
+var configFiles = map[string]string{
+	"config.json": `{
+  "selector": "byBlock",
+  "timeline": { "start": -4, "end": 0, "unit": "hours" },
+  "byBlock": { "start": 10, "end": 20 },
+  "endpoint": "http://file:8081/v1",
+  "http_retry": 5,
+  "params": { "blocks_per_session": 4, "approx_block_time_in_min": 15 },
+  "filters": { "chains": ["0001", "0002"] }
+}`,
+	"config.yaml": `selector: byBlock
+timeline:
+  start: -4
+  end: 0
+  unit: hours
+byBlock:
+  start: 10
+  end: 20
+endpoint: http://file:8081/v1
+http_retry: 5
+params:
+  blocks_per_session: 4
+  approx_block_time_in_min: 15
+filters:
+  chains: ["0001", "0002"]
+`,
+	"config.toml": `selector = "byBlock"
+endpoint = "http://file:8081/v1"
+http_retry = 5
+
+[timeline]
+start = -4
+end = 0
+unit = "hours"
+
+[byBlock]
+start = 10
+end = 20
+
+[params]
+blocks_per_session = 4
+approx_block_time_in_min = 15
+
+[filters]
+chains = ["0001", "0002"]
+`,
+}
+
+func fileConfig() Config {
+	return Config{
+		Selector:  selector.SelectorByBlock,
+		Timeline:  selector.Timeline{Start: -4, End: 0, Unit: "hours"},
+		ByBlock:   selector.ByBlock{Start: 10, End: 20},
+		Endpoint:  "http://file:8081/v1",
+		HTTPRetry: 5,
+		Params:    selector.Params{BlocksPerSession: 4, AppxBlockTimeInMinutes: 15},
+		Filters:   report.Filters{Chains: []string{"0001", "0002"}},
+	}
+}
+
+// The environment variables and the flags override the keys of the file, a zero or empty value included
+func TestConfigLayers(t *testing.T) {
+	tests := []struct {
+		name     string
+		env      map[string]string
+		args     []string
+		expected func(c *Config)
+	}{
+		{
+			name:     "file",
+			expected: func(c *Config) {},
+		},
+		{
+			name:     "zero timeline start flag",
+			args:     []string{"-timelineStart=0"},
+			expected: func(c *Config) { c.Timeline.Start = 0 },
+		},
+		{
+			name:     "zero retries env",
+			env:      map[string]string{"RELAY_COUNTER_HTTP_RETRY": "0"},
+			expected: func(c *Config) { c.HTTPRetry = 0 },
+		},
+		{
+			name:     "empty env list",
+			env:      map[string]string{"RELAY_COUNTER_FILTERS_CHAINS": ""},
+			expected: func(c *Config) { c.Filters.Chains = nil },
+		},
+		{
+			name: "env list",
+			env:  map[string]string{"RELAY_COUNTER_FILTERS_CHAINS": "0021,0040"},
+			expected: func(c *Config) {
+				c.Filters.Chains = []string{"0021", "0040"}
+			},
+		},
+		{
+			name: "flags over conflicting env",
+			env: map[string]string{
+				"RELAY_COUNTER_HTTP_RETRY":    "1",
+				"RELAY_COUNTER_ENDPOINT":      "http://env:8081/v1",
+				"RELAY_COUNTER_BYBLOCK_START": "12",
+			},
+			args: []string{"-httpRetry=2", "-endpoint=http://flag:8081/v1"},
+			expected: func(c *Config) {
+				c.HTTPRetry = 2
+				c.Endpoint = "http://flag:8081/v1"
+				c.ByBlock.Start = 12
+			},
+		},
+	}
+	for name, content := range configFiles {
+		for _, tt := range tests {
+			name, content, tt := name, content, tt
+			t.Run(name+"/"+tt.name, func(t *testing.T) {
+				c, err := loadTestConfig(t, name, content, tt.env, tt.args...)
+				if err != nil {
+					t.Fatal(err)
+				}
+				expected := fileConfig()
+				tt.expected(&expected)
+				if !reflect.DeepEqual(c, expected) {
+					t.Fatalf("expected the config %+v, got %+v", expected, c)
+				}
+			})
+		}
+	}
+}
+
+func TestInvalidEnvValue(t *testing.T) {
+	_, err := loadTestConfig(t, "config.json", configFiles["config.json"], map[string]string{"RELAY_COUNTER_HTTP_RETRY": "three"})
+	configErr, ok := err.(*ConfigError)
+	if !ok {
+		t.Fatalf("expected a config error, got %v", err)
+	}
+	expected := NewInvalidConfigValueError("RELAY_COUNTER_HTTP_RETRY", "three", kindInt)
+	if len(configErr.Errors) != 1 || configErr.Errors[0].Error() != expected.Error() {
+		t.Fatalf("expected %v, got %v", expected, configErr.Errors)
+	}
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pokt-network/relay_counter/report"
+)
+
+const (
+	CSVNodesSuffix    = "_nodes.csv"
+	CSVAppsSuffix     = "_apps.csv"
+	CSVChainsSuffix   = "_chains.csv"
+	CSVServicesSuffix = "_services.csv"
+)
+
+// Writes the node totals, app totals, per chain breakdowns and service rows in separate csv files next to file
+func writeCSVFiles(result report.Report, file string) (files []string, err error) {
+	base := strings.TrimSuffix(file, filepath.Ext(file))
+	nodeAddresses := make([]string, 0, len(result.NodeReports))
+	for address := range result.NodeReports {
+		nodeAddresses = append(nodeAddresses, address)
+	}
+	sort.Strings(nodeAddresses)
+	appAddresses := make([]string, 0, len(result.AppReports))
+	for address := range result.AppReports {
+		appAddresses = append(appAddresses, address)
+	}
+	sort.Strings(appAddresses)
+	// node totals
+	rows := [][]string{{"node", "total_relays"}}
+	for _, address := range nodeAddresses {
+		rows = append(rows, []string{address, strconv.FormatInt(result.NodeReports[address].TotalRelays, 10)})
+	}
+	if err = writeCSVFile(base+CSVNodesSuffix, rows); err != nil {
+		return
+	}
+	files = append(files, base+CSVNodesSuffix)
+	// app totals
+	rows = [][]string{{"app", "total_relays"}}
+	for _, address := range appAddresses {
+		rows = append(rows, []string{address, strconv.FormatInt(result.AppReports[address].TotalRelays, 10)})
+	}
+	if err = writeCSVFile(base+CSVAppsSuffix, rows); err != nil {
+		return
+	}
+	files = append(files, base+CSVAppsSuffix)
+	// per chain breakdowns of the nodes and apps
+	rows = [][]string{{"type", "address", "chain", "relays"}}
+	for _, address := range nodeAddresses {
+		for _, chain := range sortedChains(result.NodeReports[address].ServiceReportByChain) {
+			rows = append(rows, []string{"node", address, chain, strconv.FormatInt(result.NodeReports[address].ServiceReportByChain[chain], 10)})
+		}
+	}
+	for _, address := range appAddresses {
+		for _, chain := range sortedChains(result.AppReports[address].ServicedReportByChain) {
+			rows = append(rows, []string{"app", address, chain, strconv.FormatInt(result.AppReports[address].ServicedReportByChain[chain], 10)})
+		}
+	}
+	if err = writeCSVFile(base+CSVChainsSuffix, rows); err != nil {
+		return
+	}
+	files = append(files, base+CSVChainsSuffix)
+	// flat service rows, one per claim
+	rows = [][]string{{"node", "app", "chain", "relays"}}
+	for _, address := range nodeAddresses {
+		for _, s := range result.NodeReports[address].Service {
+			rows = append(rows, []string{address, s.Address, s.ChainID, strconv.FormatInt(s.TotalRelays, 10)})
+		}
+	}
+	if err = writeCSVFile(base+CSVServicesSuffix, rows); err != nil {
+		return
+	}
+	files = append(files, base+CSVServicesSuffix)
+	return
+}
+
+func writeCSVFile(file string, rows [][]string) error {
+	f, err := os.Create(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	w := csv.NewWriter(f)
+	if err := w.WriteAll(rows); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+func sortedChains(m map[string]int64) []string {
+	chains := make([]string, 0, len(m))
+	for chain := range m {
+		chains = append(chains, chain)
+	}
+	sort.Strings(chains)
+	return chains
+}
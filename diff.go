@@ -0,0 +1,72 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/pokt-network/relay_counter/report"
+)
+
+const (
+	CommandDiff     = "diff"
+	DiffFormatJSON  = "json"
+	DiffFormatTable = "table"
+)
+
+// Compares two report files and prints the differences
+func runDiff(args []string) {
+	fs := flag.NewFlagSet(os.Args[0]+" "+CommandDiff, flag.ExitOnError)
+	format := fs.String("format", DiffFormatTable, "output format of the diff. It can be: table (default) or json")
+	output := fs.String("output", "", "file the diff is written to, defaults to the screen")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s %s [flags] <old report> <new report>\n", os.Args[0], CommandDiff)
+		fs.PrintDefaults()
+	}
+	lf := newLogFlags(fs)
+	_ = fs.Parse(args)
+	lf.apply()
+	if fs.NArg() != 2 {
+		fs.Usage()
+		os.Exit(2)
+	}
+	if *format != DiffFormatTable && *format != DiffFormatJSON {
+		fatal(NewInvalidDiffFormatError(*format))
+	}
+
+	oldReport, err := report.ReadFile(fs.Arg(0))
+	if err != nil {
+		fatal(err)
+	}
+	newReport, err := report.ReadFile(fs.Arg(1))
+	if err != nil {
+		fatal(err)
+	}
+	d := report.Compare(oldReport, newReport)
+	d.OldFile, d.NewFile = fs.Arg(0), fs.Arg(1)
+
+	w := io.Writer(os.Stdout)
+	if *output != "" {
+		f, err := os.Create(*output)
+		if err != nil {
+			fatal(err)
+		}
+		defer f.Close()
+		w = f
+	}
+	switch *format {
+	case DiffFormatTable:
+		err = d.WriteTable(w)
+	case DiffFormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "    ")
+		err = enc.Encode(d)
+	default:
+		err = NewInvalidDiffFormatError(*format)
+	}
+	if err != nil {
+		fatal(err)
+	}
+}
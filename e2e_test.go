@@ -0,0 +1,299 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pokt-network/relay_counter/client"
+	"github.com/pokt-network/relay_counter/fakenode"
+	"github.com/pokt-network/relay_counter/indexer"
+	"github.com/pokt-network/relay_counter/logging"
+	"github.com/pokt-network/relay_counter/report"
+	tmlog "github.com/tendermint/tendermint/libs/log"
+)
+
+const (
+	// holds the scenarios, fixtures and golden reports of the end to end tests
+	e2eDir = "testdata/e2e"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files with the generated reports")
+
+// A run of the indexer against the fake node, its report is compared with the golden file.
+// The fixture and golden paths are relative to the scenarios file
+type scenario struct {
+	Name            string         `json:"name"`
+	Description     string         `json:"description"`
+	Fixture         string         `json:"fixture"`
+	MaxPerPage      int            `json:"max_per_page"`
+	FailFirst       int            `json:"fail_first"`
+	FailingHeights  []int64        `json:"failing_heights"`
+	FailingSupplies []int64        `json:"failing_supplies"`
+	Config          Config         `json:"config"`
+	Golden          string         `json:"golden"`
+	Incomplete      bool           `json:"incomplete"`   // the run is expected to return an incomplete report
+	MinRequests     map[string]int `json:"min_requests"` // minimum requests the fake node must receive by path
+}
+
+// Runs the end to end scenarios against an in-process fake node and compares the reports with the golden files
+func TestE2E(t *testing.T) {
+	if !testing.Verbose() {
+		logging.SetLogger(tmlog.NewNopLogger())
+	}
+	for _, s := range readScenarios(t) {
+		s := s
+		t.Run(s.Name, func(t *testing.T) {
+			s.run(t, e2eDir)
+		})
+	}
+}
+
+func readScenarios(t *testing.T) (scenarios []scenario) {
+	bz, err := ioutil.ReadFile(filepath.Join(e2eDir, "scenarios.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(bz, &scenarios); err != nil {
+		t.Fatal(err)
+	}
+	return scenarios
+}
+
+func (s scenario) run(t *testing.T, dir string) {
+	node, server := s.startNode(t, dir)
+	defer server.Close()
+
+	c := s.Config
+	c.Endpoint = server.URL + fakenode.VersionPath
+	result := s.generate(t, c, c.Client())
+	for path, min := range s.MinRequests {
+		if got := node.Requests(path); got < min {
+			t.Errorf("expected at least %d requests to %s, got %d", min, path, got)
+		}
+	}
+	bz, err := json.MarshalIndent(normalizeReport(result), "", "    ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	bz = append(bz, '\n')
+	golden := filepath.Join(dir, s.Golden)
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(golden), os.ModePerm); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(golden, bz, 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	expected, err := ioutil.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(expected, bz) {
+		t.Fatalf("the report differs from %s at %s, run with -update if the change is expected", golden, firstDiff(expected, bz))
+	}
+}
+
+// Serves the fixture of the scenario with its failures
+func (s scenario) startNode(t *testing.T, dir string) (*fakenode.Node, *httptest.Server) {
+	fixture, err := fakenode.LoadFixture(filepath.Join(dir, s.Fixture))
+	if err != nil {
+		t.Fatal(err)
+	}
+	fixture.MaxPerPage = s.MaxPerPage
+	fixture.FailFirst = s.FailFirst
+	fixture.FailingHeights = s.FailingHeights
+	fixture.FailingSupplies = s.FailingSupplies
+	node, err := fakenode.New(fixture)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return node, httptest.NewServer(node)
+}
+
+// Generates the report of the config, incomplete when the scenario expects it
+func (s scenario) generate(t *testing.T, c Config, pocket *client.Client) report.Report {
+	opts, err := c.IndexerOptions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := pocket.Test(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	result, err := indexer.GenerateReport(context.Background(), pocket, opts)
+	if _, ok := err.(*report.IncompleteError); ok && s.Incomplete {
+		err = nil
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Incomplete != s.Incomplete {
+		t.Fatalf("expected the report to be incomplete: %t, got incomplete: %t", s.Incomplete, result.Incomplete)
+	}
+	return result
+}
+
+// A run recorded against the fake node replays to the same report once the node is gone, its failures included
+func TestRecordReplay(t *testing.T) {
+	if !testing.Verbose() {
+		logging.SetLogger(tmlog.NewNopLogger())
+	}
+	for _, s := range readScenarios(t) {
+		s := s
+		if s.Name != "byblock" && s.Name != "failed_supply" {
+			continue
+		}
+		t.Run(s.Name, func(t *testing.T) {
+			archive := filepath.Join(t.TempDir(), "run.json.gz")
+			_, server := s.startNode(t, e2eDir)
+			recorded := s.Config
+			recorded.Endpoint = server.URL + fakenode.VersionPath
+			pocket, err := runClient(&recorded, archive, "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			expected := s.generate(t, recorded, pocket)
+			saveRecording()
+			server.Close()
+
+			replayed := s.Config
+			pocket, err = runClient(&replayed, "", archive)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if replayed.Endpoint != recorded.Endpoint {
+				t.Fatalf("expected the endpoint of the recording %s, got %s", recorded.Endpoint, replayed.Endpoint)
+			}
+			got := s.generate(t, replayed, pocket)
+			expectedBz, err := json.MarshalIndent(normalizeReport(expected), "", "    ")
+			if err != nil {
+				t.Fatal(err)
+			}
+			gotBz, err := json.MarshalIndent(normalizeReport(got), "", "    ")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(expectedBz, gotBz) {
+				t.Fatalf("the replayed report differs from the recorded one at %s", firstDiff(expectedBz, gotBz))
+			}
+		})
+	}
+}
+
+const (
+	// set in the environment of the process run by TestRecordingSavedOnFailure
+	envFailingRunArchive  = "RELAY_COUNTER_TEST_ARCHIVE"
+	envFailingRunEndpoint = "RELAY_COUNTER_TEST_ENDPOINT"
+)
+
+// A run stopped by fatal still saves its recording, which replays the failure
+func TestRecordingSavedOnFailure(t *testing.T) {
+	if archive := os.Getenv(envFailingRunArchive); archive != "" {
+		c := Config{Endpoint: os.Getenv(envFailingRunEndpoint)}
+		pocket, err := runClient(&c, archive, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := pocket.GetSupply(context.Background(), 29); err != nil {
+			fatal(err)
+		}
+		t.Fatal("expected the supply request to fail")
+	}
+	s := scenario{Fixture: "chain.json", FailingSupplies: []int64{29}}
+	_, server := s.startNode(t, e2eDir)
+	endpoint := server.URL + fakenode.VersionPath
+	archive := filepath.Join(t.TempDir(), "run.json.gz")
+	cmd := exec.Command(os.Args[0], "-test.run=^TestRecordingSavedOnFailure$")
+	cmd.Env = append(os.Environ(), envFailingRunArchive+"="+archive, envFailingRunEndpoint+"="+endpoint)
+	out, err := cmd.CombinedOutput()
+	server.Close()
+	if exitErr, ok := err.(*exec.ExitError); !ok || exitErr.ExitCode() != 1 {
+		t.Fatalf("expected the run to exit with status 1, got %v: %s", err, out)
+	}
+
+	recorded, err := client.LoadArchive(archive)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(recorded.Interactions) != 1 || recorded.Interactions[0].Path != fakenode.VersionPath+client.SupplyPath ||
+		recorded.Interactions[0].Status != http.StatusInternalServerError {
+		t.Fatalf("expected the failed supply request in the recording, got %+v", recorded.Interactions)
+	}
+	c := Config{}
+	pocket, err := runClient(&c, "", archive)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Endpoint != endpoint {
+		t.Fatalf("expected the endpoint of the recording %s, got %s", endpoint, c.Endpoint)
+	}
+	if _, err := pocket.GetSupply(context.Background(), 29); err == nil {
+		t.Fatal("expected the replayed supply request to fail")
+	}
+}
+
+// Clears what changes from run to run (the time of the run, the port of the fake node, the build)
+// and sorts the slices built from maps, so the report can be compared byte for byte
+func normalizeReport(r report.Report) report.Report {
+	if r.Metadata != nil {
+		metadata := *r.Metadata
+		metadata.ToolVersion = ""
+		metadata.Endpoint = ""
+		metadata.ConfigHash = ""
+		metadata.GeneratedAt = time.Time{}
+		r.Metadata = &metadata
+	}
+	for address, nodeReport := range r.NodeReports {
+		sortServiceReports(nodeReport.Service)
+		r.NodeReports[address] = nodeReport
+	}
+	for address, appReport := range r.AppReports {
+		sortServiceReports(appReport.ServicedBy)
+		r.AppReports[address] = appReport
+	}
+	sort.SliceStable(r.FailedHeights, func(i, j int) bool {
+		if r.FailedHeights[i].Height != r.FailedHeights[j].Height {
+			return r.FailedHeights[i].Height < r.FailedHeights[j].Height
+		}
+		return r.FailedHeights[i].Message < r.FailedHeights[j].Message
+	})
+	return r
+}
+
+func sortServiceReports(services []report.ServiceReport) {
+	sort.Slice(services, func(i, j int) bool {
+		if services[i].Address != services[j].Address {
+			return services[i].Address < services[j].Address
+		}
+		if services[i].ChainID != services[j].ChainID {
+			return services[i].ChainID < services[j].ChainID
+		}
+		return services[i].TotalRelays < services[j].TotalRelays
+	})
+}
+
+// The first line that differs, to point at the mismatch without printing the whole reports
+func firstDiff(expected, got []byte) string {
+	expectedLines := strings.Split(string(expected), "\n")
+	gotLines := strings.Split(string(got), "\n")
+	for i := 0; i < len(expectedLines) && i < len(gotLines); i++ {
+		if expectedLines[i] != gotLines[i] {
+			return fmt.Sprintf("line %d: expected %q, got %q", i+1, strings.TrimSpace(expectedLines[i]), strings.TrimSpace(gotLines[i]))
+		}
+	}
+	return fmt.Sprintf("expected %d lines, got %d", len(expectedLines), len(gotLines))
+}
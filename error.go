@@ -2,34 +2,197 @@ package main
 
 import (
 	"fmt"
+	"strings"
+	"time"
 )
 
-const (
-	HTTPStatusCodeError = "status code non 200"
-	InvalidUnitError    = "ERROR: unrecognized unit: "
-	Default
-)
+func NewInvalidFormatError(format string) error {
+	return fmt.Errorf("ERROR: unrecognized output format: %s, valid formats: (json, csv, sqlite, markdown, html)", format)
+}
+
+func NewInvalidBlockTimeError(blockTime int64) error {
+	return fmt.Errorf("ERROR: params.approx_block_time_in_min must be greater than 0, got %d", blockTime)
+}
+
+func NewMethodNotAllowedError(method string) error {
+	return fmt.Errorf("ERROR: method %s not allowed", method)
+}
+
+func NewInvalidQueryParamError(param, value string) error {
+	return fmt.Errorf("ERROR: invalid value for query param %s: %q", param, value)
+}
+
+func NewInvalidBlockRangeError(start, end int64) error {
+	return fmt.Errorf("ERROR: invalid block range: start %d must be greater than 0 and less than end %d", start, end)
+}
+
+func NewRangeTooLargeError(start, end, maxRange int64) error {
+	return fmt.Errorf("ERROR: block range %d to %d is too large, at most %d heights can be requested", start, end, maxRange)
+}
+
+func NewInvalidWindowError(window time.Duration, blockTimeInMin int64) error {
+	return fmt.Errorf("ERROR: invalid window %s, it must span at least one block of %d minutes", window, blockTimeInMin)
+}
+
+func NewHeightNotAvailableError(height, latestHeight int64) error {
+	return fmt.Errorf("ERROR: height %d is not available yet, the latest height is %d", height, latestHeight)
+}
+
+func NewInvalidDiffFormatError(format string) error {
+	return fmt.Errorf("ERROR: unrecognized diff format: %s, valid formats: (table, json)", format)
+}
+
+func NewOutdatedSchemaError(file string) error {
+	return fmt.Errorf("ERROR: %s is outdated, run go generate to update it", file)
+}
+
+func NewInvalidConfigFileError(file string, err error) error {
+	return fmt.Errorf("ERROR: unable to parse config file %s: %s", file, err.Error())
+}
+
+func NewRecordAndReplayError() error {
+	return fmt.Errorf("ERROR: -record and -replay can't be used together")
+}
+
+func NewInvalidProgressError(mode string) error {
+	return fmt.Errorf("ERROR: unrecognized progress mode: %s, valid modes: (auto, live, log, off)", mode)
+}
+
+func NewMissingEndpointError() error {
+	return fmt.Errorf("ERROR: endpoint is required, e.g. http://localhost:8081/v1")
+}
+
+func NewInvalidEndpointURLError(endpoint string) error {
+	return fmt.Errorf("ERROR: endpoint %q is not a valid URL, e.g. http://localhost:8081/v1", endpoint)
+}
+
+func NewInvalidHTTPRetryError(retries int) error {
+	return fmt.Errorf("ERROR: http_retry must be 0 or more, got %d", retries)
+}
+
+// Every problem found in the config once the command line overrides are applied
+type ConfigError struct {
+	File   string
+	Errors []error
+}
+
+func (e *ConfigError) Error() string {
+	lines := make([]string, 0, len(e.Errors)+1)
+	lines = append(lines, fmt.Sprintf("ERROR: invalid config %s, %d problem(s):", e.File, len(e.Errors)))
+	for _, err := range e.Errors {
+		message := err.Error()
+		for strings.HasPrefix(message, "ERROR: ") {
+			message = strings.TrimPrefix(message, "ERROR: ")
+		}
+		lines = append(lines, "  - "+message)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func NewUnsupportedConfigFileError(file string) error {
+	return fmt.Errorf("ERROR: unsupported config file %s, the extension must be .json, .yaml, .yml or .toml", file)
+}
+
+func NewInvalidConfigValueError(source, value, kind string) error {
+	return fmt.Errorf("ERROR: invalid value %q for %s, expected a value of type %s", value, source, kind)
+}
+
+func NewInvalidConfigError(err error) error {
+	return fmt.Errorf("ERROR: invalid config: %s", err.Error())
+}
+
+func NewInvalidWebhookError(name string, err error) error {
+	return fmt.Errorf("ERROR: webhook %s: %s", name, strings.TrimPrefix(err.Error(), "ERROR: "))
+}
+
+func NewDuplicateAlertRuleError(name string) error {
+	return fmt.Errorf("ERROR: alert rule %s is defined more than once", name)
+}
+
+func NewInvalidAlertRuleError(name string, err error) error {
+	return fmt.Errorf("ERROR: alert rule %s: %s", name, strings.TrimPrefix(err.Error(), "ERROR: "))
+}
+
+func NewUnreadablePreviousReportError(file string) error {
+	return fmt.Errorf("ERROR: the previous report %s can't be read back, only json reports can be compared", file)
+}
+
+func NewUnknownProfileError(profile string, profiles []string) error {
+	return fmt.Errorf("ERROR: unknown profile %s, the profiles are: %s", profile, strings.Join(profiles, ", "))
+}
+
+func NewInvalidProfileError(profile string) error {
+	return fmt.Errorf("ERROR: profile %s of the config file must be a map of config keys", profile)
+}
+
+func NewInvalidProfilesError() error {
+	return fmt.Errorf("ERROR: profiles of the config file must be a map of profile names to config keys")
+}
+
+func NewInvalidBatchSplitError(split int64) error {
+	return fmt.Errorf("ERROR: batch.split must be 0 (no split) or greater, got %d", split)
+}
+
+func NewDuplicateBatchRangeError(name string) error {
+	return fmt.Errorf("ERROR: batch range %s is defined more than once", name)
+}
+
+func NewInvalidBatchRangeNameError(name string) error {
+	return fmt.Errorf("ERROR: invalid batch range name %s, it names the results file so it can't contain a path separator or be %s", name, BatchCombined)
+}
+
+func NewMixedCombinedBatchError() error {
+	return fmt.Errorf("ERROR: the ranges of a combined batch must share the selector, and the unit for timeline ranges")
+}
+
+func NewOverlappingBatchRangesError(name1, name2 string) error {
+	return fmt.Errorf("ERROR: batch ranges %s and %s overlap, the ranges of a combined batch must be contiguous", name1, name2)
+}
+
+func NewNonContiguousBatchRangesError(name1, name2 string) error {
+	return fmt.Errorf("ERROR: batch ranges %s and %s leave a gap between them, the ranges of a combined batch must be contiguous", name1, name2)
+}
+
+func NewInvalidBatchRangeError(name string, err error) error {
+	return fmt.Errorf("ERROR: batch range %s: %s", name, strings.TrimPrefix(err.Error(), "ERROR: "))
+}
+
+func NewMissingScheduleError(source string) error {
+	return fmt.Errorf("ERROR: %s has no schedule, the jobs to run are listed under schedule.jobs", source)
+}
+
+func NewMissingScheduleJobsError() error {
+	return fmt.Errorf("ERROR: schedule.jobs must list at least one job")
+}
+
+func NewMissingJobNameError(index int) error {
+	return fmt.Errorf("ERROR: job %d of the schedule has no name", index)
+}
+
+func NewDuplicateJobError(name string) error {
+	return fmt.Errorf("ERROR: job %s is defined more than once", name)
+}
 
-func NewHTTPStatusCode(code int, body string) error {
-	return fmt.Errorf(HTTPStatusCodeError+": %d: With body %s", code, body)
+func NewInvalidJobNameError(name string) error {
+	return fmt.Errorf("ERROR: invalid job name %s, it names the results files so it can't contain a path separator", name)
 }
 
-func NewInvalidStartEndError(s, e int64, unit string) error {
-	return fmt.Errorf("ERROR: unable to interpret timeline: (Start) %d%s Ago to (End) %d%s Ago is not a valid range. Start must come before End", s, unit, e, unit)
+func NewInvalidJobError(name string, err error) error {
+	return fmt.Errorf("ERROR: job %s: %s", name, strings.TrimPrefix(err.Error(), "ERROR: "))
 }
 
-func NewInvalidUnitError(unit string) error {
-	return fmt.Errorf("ERROR: %s%s, valid units: (minutes, hours, days, weeks, blocks, sessions)", InvalidUnitError, unit)
+func NewInvalidCronError(spec string, err error) error {
+	return fmt.Errorf("ERROR: invalid cron schedule %q: %s", spec, err.Error())
 }
 
-func NewInvalidMinimumHeightError(minHeight int64) error {
-	return fmt.Errorf("ERROR: the start height is less than 0 (%d), ensure your pocket client is synced and the start and end values are within bounds", minHeight)
+func NewInvalidSchedulePeriodError(period string) error {
+	return fmt.Errorf("ERROR: unrecognized period: %s, valid periods: (hour, day, week, month)", period)
 }
 
-func NewProofMsgInterfaceError() error {
-	return fmt.Errorf("ERROR: unable to convert interface to ProofMsg")
+func NewPeriodAndSelectorError() error {
+	return fmt.Errorf("ERROR: a job selects its range either with period or with selector, not both")
 }
 
-func NewPublicKeyError() error {
-	return fmt.Errorf("ERROR: unable to convert string public key into ED25519 public key")
+func NewInvalidRetentionError(key string, value interface{}) error {
+	return fmt.Errorf("ERROR: invalid retention.%s %v, it must be positive, or omitted to keep every report", key, value)
 }
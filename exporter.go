@@ -0,0 +1,175 @@
+package main
+
+import (
+	"flag"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/pokt-network/relay_counter/indexer"
+	"github.com/pokt-network/relay_counter/logging"
+	"github.com/pokt-network/relay_counter/report"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const (
+	MetricsNamespace = "relay_counter"
+	MetricsPath      = "/metrics"
+)
+
+// The prometheus metrics of the latest report, the gauges are reset on every update
+type Metrics struct {
+	NodeRelays     *prometheus.GaugeVec
+	AppRelays      *prometheus.GaugeVec
+	ChainRelays    *prometheus.GaugeVec
+	GroupRelays    *prometheus.GaugeVec
+	BadTxs         *prometheus.GaugeVec
+	TotalRelays    prometheus.Gauge
+	Challenges     prometheus.Gauge
+	GoodTxs        prometheus.Gauge
+	ProofTxs       prometheus.Gauge
+	Minted         prometheus.Gauge
+	MinHeight      prometheus.Gauge
+	LatestHeight   prometheus.Gauge
+	LastUpdate     prometheus.Gauge
+	Updates        prometheus.Counter
+	UpdateDuration prometheus.Histogram
+}
+
+func NewMetrics(registry prometheus.Registerer) *Metrics {
+	gaugeVec := func(name, help string, labels ...string) *prometheus.GaugeVec {
+		return prometheus.NewGaugeVec(prometheus.GaugeOpts{Namespace: MetricsNamespace, Name: name, Help: help}, labels)
+	}
+	gauge := func(name, help string) prometheus.Gauge {
+		return prometheus.NewGauge(prometheus.GaugeOpts{Namespace: MetricsNamespace, Name: name, Help: help})
+	}
+	m := &Metrics{
+		NodeRelays:   gaugeVec("node_relays", "Relays serviced by the node in the report range.", "address", "chain"),
+		AppRelays:    gaugeVec("app_relays", "Relays consumed by the app in the report range.", "address", "chain"),
+		ChainRelays:  gaugeVec("chain_relays", "Relays of the chain in the report range.", "chain"),
+		GroupRelays:  gaugeVec("group_relays", "Relays of the group in the report range.", "group", "chain"),
+		BadTxs:       gaugeVec("bad_txs", "Failed transactions in the report range by error code.", "code"),
+		TotalRelays:  gauge("total_relays", "Network wide relays completed in the report range."),
+		Challenges:   gauge("total_challenges", "Network wide challenges completed in the report range."),
+		GoodTxs:      gauge("good_txs", "Successful transactions in the report range."),
+		ProofTxs:     gauge("proof_txs", "Proof transactions in the report range."),
+		Minted:       gauge("minted", "Supply minted in the report range."),
+		MinHeight:    gauge("min_height", "First height of the report range."),
+		LatestHeight: gauge("latest_indexed_height", "Last height of the report range."),
+		LastUpdate:   gauge("last_update_timestamp_seconds", "Unix time of the last successful report."),
+		Updates: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: MetricsNamespace, Name: "updates_total", Help: "Reports generated since the exporter started.",
+		}),
+		UpdateDuration: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: MetricsNamespace, Name: "update_duration_seconds", Help: "Time taken to generate a report.",
+			Buckets: prometheus.ExponentialBuckets(1, 4, 8),
+		}),
+	}
+	registry.MustRegister(m.NodeRelays, m.AppRelays, m.ChainRelays, m.GroupRelays, m.BadTxs, m.TotalRelays, m.Challenges,
+		m.GoodTxs, m.ProofTxs, m.Minted, m.MinHeight, m.LatestHeight, m.LastUpdate, m.Updates, m.UpdateDuration)
+	return m
+}
+
+// Sets the metrics to the values of the report
+func (m *Metrics) Update(result report.Report) {
+	m.NodeRelays.Reset()
+	m.AppRelays.Reset()
+	m.ChainRelays.Reset()
+	m.GroupRelays.Reset()
+	m.BadTxs.Reset()
+	for address, nodeReport := range result.NodeReports {
+		for chain, relays := range nodeReport.ServiceReportByChain {
+			m.NodeRelays.WithLabelValues(address, chain).Set(float64(relays))
+			m.ChainRelays.WithLabelValues(chain).Add(float64(relays))
+		}
+	}
+	for address, appReport := range result.AppReports {
+		for chain, relays := range appReport.ServicedReportByChain {
+			m.AppRelays.WithLabelValues(address, chain).Set(float64(relays))
+		}
+	}
+	for name, groupReport := range result.GroupReports {
+		for chain, relays := range groupReport.ReportByChain {
+			m.GroupRelays.WithLabelValues(name, chain).Set(float64(relays))
+		}
+	}
+	for code, count := range result.BadTxsMap {
+		m.BadTxs.WithLabelValues(strconv.FormatUint(uint64(code), 10)).Set(float64(count))
+	}
+	m.TotalRelays.Set(float64(result.TotalRelaysCompleted))
+	m.Challenges.Set(float64(result.TotalChallengesCompleted))
+	m.GoodTxs.Set(float64(result.TotalGoodTxs))
+	m.ProofTxs.Set(float64(result.TotalProofTxs))
+	m.Minted.Set(float64(result.TotalMinted))
+	m.MinHeight.Set(float64(result.BlockReport.MinHeight))
+	m.LatestHeight.Set(float64(result.BlockReport.MaxHeight))
+	m.LastUpdate.SetToCurrentTime()
+	m.Updates.Inc()
+}
+
+// Runs continuously, regenerating the report every interval and exposing it as prometheus metrics
+func runExporter(args []string) {
+	fs := flag.NewFlagSet(os.Args[0]+" "+CommandExporter, flag.ExitOnError)
+	listen := fs.String("listen", ":8083", "address the metrics endpoint listens on")
+	interval := fs.Duration("interval", 15*time.Minute, "time between report updates")
+	cf := newConfigFlags(fs)
+	lf := newLogFlags(fs)
+	_ = fs.Parse(args)
+	lf.apply()
+
+	c, err := cf.load()
+	if err != nil {
+		fatal(err)
+	}
+
+	opts, err := c.IndexerOptions()
+	if err != nil {
+		fatal(err)
+	}
+
+	ctx := interruptContext()
+	pocket := c.Client()
+	logging.Info("Testing the pocket endpoint", "endpoint", pocket.Endpoint())
+	if err := pocket.Test(ctx); err != nil {
+		fatal(err)
+	}
+
+	registry := prometheus.NewRegistry()
+	metrics := NewMetrics(registry)
+	mux := http.NewServeMux()
+	mux.Handle(MetricsPath, promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
+	server := &http.Server{Addr: *listen, Handler: mux}
+	go func() {
+		logging.Info("Serving metrics", "address", *listen, "path", MetricsPath)
+		if err := server.ListenAndServe(); err != http.ErrServerClosed {
+			fatal(err)
+		}
+	}()
+
+	for {
+		start := time.Now()
+		result, err := indexer.GenerateReport(ctx, pocket, opts)
+		if ctx.Err() != nil {
+			break
+		}
+		if err != nil {
+			logging.Error("Could not generate the report", "err", err.Error())
+		} else {
+			metrics.Update(result)
+			metrics.UpdateDuration.Observe(time.Since(start).Seconds())
+			logging.Info("Metrics updated", "min_height", result.BlockReport.MinHeight, "max_height", result.BlockReport.MaxHeight)
+		}
+		if !sleepContext(ctx, *interval) {
+			break
+		}
+	}
+
+	logging.Info("Interrupted, shutting down the metrics endpoint")
+	shutdownCtx, cancel := indexer.FinishContext(ctx)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		logging.Error("Could not shut down the metrics endpoint", "err", err.Error())
+	}
+}
@@ -0,0 +1,21 @@
+package fakenode
+
+import (
+	"fmt"
+)
+
+func NewInvalidFixtureError(file string, err error) error {
+	return fmt.Errorf("ERROR: unable to parse fixture file %s: %s", file, err.Error())
+}
+
+func NewInvalidKeyError(height int64, pkHex string) error {
+	return fmt.Errorf("ERROR: invalid public key %q in the fixture block %d", pkHex, height)
+}
+
+func NewTxEncodeError(height int64, err error) error {
+	return fmt.Errorf("ERROR: unable to encode the fixture tx at height %d: %s", height, err.Error())
+}
+
+func NewHeightOutOfRangeError(height int64) error {
+	return fmt.Errorf("ERROR: height %d is out of the fixture range", height)
+}
@@ -0,0 +1,58 @@
+package fakenode
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"time"
+)
+
+// The chain served by the fake node. Block times and supplies are derived from the height,
+// the blocks list the txs of the heights that have any
+type Fixture struct {
+	LatestHeight     int64           `json:"latest_height"`
+	GenesisTime      time.Time       `json:"genesis_time"`
+	BlockTimeMinutes int64           `json:"block_time_minutes"`
+	GenesisSupply    int64           `json:"genesis_supply"`
+	MintedPerBlock   int64           `json:"minted_per_block"`
+	MaxPerPage       int             `json:"max_per_page"`     // caps the txs of a blocktxs page, 0 for no cap
+	FailFirst        int             `json:"fail_first"`       // blocktxs and claims requests of every height answered with a 500 before succeeding
+	FailingHeights   []int64         `json:"failing_heights"`  // heights whose blocktxs requests always fail
+	FailingSupplies  []int64         `json:"failing_supplies"` // heights whose supply requests always fail
+	Blocks           map[int64]Block `json:"blocks"`
+}
+
+type Block struct {
+	Proofs  []Proof  `json:"proofs"`
+	GoodTxs int      `json:"good_txs"` // successful txs that are not proofs
+	BadTxs  []uint32 `json:"bad_txs"`  // result codes of the failed txs
+}
+
+// A proof tx of the block, its claim is served at the previous height
+type Proof struct {
+	Node      string `json:"node"` // public key of the servicer
+	App       string `json:"app"`  // public key of the app
+	Chain     string `json:"chain"`
+	Relays    int64  `json:"relays"`
+	Challenge bool   `json:"challenge"`
+	NoClaim   bool   `json:"no_claim"` // leaves the claim out, so the proof can't be matched
+}
+
+func LoadFixture(file string) (f Fixture, err error) {
+	bz, err := ioutil.ReadFile(file)
+	if err != nil {
+		return f, err
+	}
+	err = json.Unmarshal(bz, &f)
+	if err != nil {
+		return f, NewInvalidFixtureError(file, err)
+	}
+	return f, nil
+}
+
+func (f Fixture) BlockTime(height int64) time.Time {
+	return f.GenesisTime.Add(time.Duration(height*f.BlockTimeMinutes) * time.Minute).UTC()
+}
+
+func (f Fixture) Supply(height int64) int64 {
+	return f.GenesisSupply + height*f.MintedPerBlock
+}
@@ -0,0 +1,251 @@
+// Package fakenode serves the RPC of a pocket-core node from fixture data, so the indexer can run without a real node.
+package fakenode
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/pokt-network/pocket-core/crypto"
+	sdk "github.com/pokt-network/pocket-core/types"
+	"github.com/pokt-network/pocket-core/x/auth"
+	authTypes "github.com/pokt-network/pocket-core/x/auth/types"
+	nodeTypes "github.com/pokt-network/pocket-core/x/nodes/types"
+	pcTypes "github.com/pokt-network/pocket-core/x/pocketcore/types"
+	"github.com/pokt-network/relay_counter/client"
+	abci "github.com/tendermint/tendermint/abci/types"
+	coretypes "github.com/tendermint/tendermint/rpc/core/types"
+	tmTypes "github.com/tendermint/tendermint/types"
+)
+
+const (
+	VersionPath = "/v1"
+)
+
+// The fake node, an http.Handler serving the version endpoint and the queries used by the client under VersionPath
+type Node struct {
+	fixture         Fixture
+	txs             map[int64][]*coretypes.ResultTx
+	claims          map[int64][]pcTypes.MsgClaim
+	failing         map[int64]bool
+	failingSupplies map[int64]bool
+	mu              sync.Mutex
+	requests        map[string]int
+}
+
+// Builds the txs and claims of the fixture, a proof at height h has its claim at height h - 1
+func New(f Fixture) (*Node, error) {
+	n := &Node{
+		fixture:         f,
+		txs:             make(map[int64][]*coretypes.ResultTx),
+		claims:          make(map[int64][]pcTypes.MsgClaim),
+		failing:         make(map[int64]bool),
+		requests:        make(map[string]int),
+		failingSupplies: make(map[int64]bool),
+	}
+	for _, height := range f.FailingHeights {
+		n.failing[height] = true
+	}
+	for _, height := range f.FailingSupplies {
+		n.failingSupplies[height] = true
+	}
+	for height, block := range f.Blocks {
+		for _, p := range block.Proofs {
+			nodePK, err := crypto.NewPublicKey(p.Node)
+			if err != nil {
+				return nil, NewInvalidKeyError(height, p.Node)
+			}
+			if _, err := crypto.NewPublicKey(p.App); err != nil {
+				return nil, NewInvalidKeyError(height, p.App)
+			}
+			evidenceType := pcTypes.RelayEvidence
+			if p.Challenge {
+				evidenceType = pcTypes.ChallengeEvidence
+			}
+			tx := auth.StdTx{
+				Msg: pcTypes.MsgProof{
+					Leaf: pcTypes.RelayProof{
+						ServicerPubKey:     p.Node,
+						Blockchain:         p.Chain,
+						SessionBlockHeight: 1,
+					},
+					EvidenceType: evidenceType,
+				},
+				Fee:       sdk.NewCoins(sdk.NewCoin(sdk.DefaultStakeDenom, sdk.NewInt(10000))),
+				Signature: authTypes.StdSignature{PublicKey: nodePK},
+				Entropy:   int64(len(n.txs[height])),
+			}
+			if err := n.addTx(height, tx, 0); err != nil {
+				return nil, err
+			}
+			if p.NoClaim {
+				continue
+			}
+			n.claims[height-1] = append(n.claims[height-1], pcTypes.MsgClaim{
+				SessionHeader: pcTypes.SessionHeader{
+					ApplicationPubKey:  p.App,
+					Chain:              p.Chain,
+					SessionBlockHeight: 1,
+				},
+				TotalProofs:  p.Relays,
+				FromAddress:  sdk.Address(nodePK.Address()),
+				EvidenceType: evidenceType,
+			})
+		}
+		for i := 0; i < block.GoodTxs+len(block.BadTxs); i++ {
+			var code uint32
+			if i >= block.GoodTxs {
+				code = block.BadTxs[i-block.GoodTxs]
+			}
+			if err := n.addTx(height, sendTx(int64(i)), code); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return n, nil
+}
+
+// A successful send, the txs that are not proofs only need to decode
+func sendTx(entropy int64) auth.StdTx {
+	pk := crypto.Ed25519PrivateKey{}.GenPrivateKey().PublicKey()
+	return auth.StdTx{
+		Msg: nodeTypes.MsgSend{
+			FromAddress: sdk.Address(pk.Address()),
+			ToAddress:   sdk.Address(pk.Address()),
+			Amount:      sdk.NewInt(1),
+		},
+		Fee:       sdk.NewCoins(sdk.NewCoin(sdk.DefaultStakeDenom, sdk.NewInt(10000))),
+		Signature: authTypes.StdSignature{PublicKey: pk},
+		Entropy:   entropy,
+	}
+}
+
+func (n *Node) addTx(height int64, tx auth.StdTx, code uint32) error {
+	bz, err := client.MarshalTx(tx, height)
+	if err != nil {
+		return NewTxEncodeError(height, err)
+	}
+	n.txs[height] = append(n.txs[height], &coretypes.ResultTx{
+		Hash:     tmTypes.Tx(bz).Hash(),
+		Height:   height,
+		Index:    uint32(len(n.txs[height])),
+		TxResult: abci.ResponseDeliverTx{Code: code},
+		Tx:       bz,
+	})
+	return nil
+}
+
+// The number of requests received on the path, failed ones included
+func (n *Node) Requests(path string) int {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.requests[path]
+}
+
+func (n *Node) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !strings.HasPrefix(r.URL.Path, VersionPath) {
+		http.NotFound(w, r)
+		return
+	}
+	path := strings.TrimPrefix(r.URL.Path, VersionPath)
+	if path == "" || path == "/" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	params := client.PaginatedHeightParams{}
+	if bz, err := ioutil.ReadAll(r.Body); err == nil && len(bz) != 0 {
+		if err := json.Unmarshal(bz, &params); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	if n.shouldFail(path, params) {
+		http.Error(w, "fake failure", http.StatusInternalServerError)
+		return
+	}
+	var body []byte
+	var err error
+	switch path {
+	case client.HeightPath:
+		body, err = json.Marshal(client.HeightRPCResponse{Height: n.fixture.LatestHeight})
+	case client.BlockPath:
+		if params.Height < 0 || params.Height > n.fixture.LatestHeight {
+			http.Error(w, NewHeightOutOfRangeError(params.Height).Error(), http.StatusBadRequest)
+			return
+		}
+		body, err = client.MarshalJSON(&coretypes.ResultBlock{Block: &tmTypes.Block{Header: tmTypes.Header{
+			Height: params.Height,
+			Time:   n.fixture.BlockTime(params.Height),
+		}}})
+	case client.BlockTxsPath:
+		body, err = json.Marshal(n.blockTxsPage(params))
+	case client.ClaimsPath:
+		claims := n.claims[params.Height]
+		if claims == nil {
+			claims = make([]pcTypes.MsgClaim, 0)
+		}
+		body, err = json.Marshal(client.ClaimsRPCResponse{Claims: claims, Total: 1, Page: 1})
+	case client.SupplyPath:
+		body, err = json.Marshal(client.SupplyRPCResponse{Total: strconv.FormatInt(n.fixture.Supply(params.Height), 10)})
+	default:
+		http.NotFound(w, r)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(body)
+}
+
+// Counts the request and tells whether it is answered with a failure
+func (n *Node) shouldFail(path string, params client.PaginatedHeightParams) bool {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.requests[path]++
+	if path == client.BlockTxsPath && n.failing[params.Height] {
+		return true
+	}
+	if path == client.SupplyPath && n.failingSupplies[params.Height] {
+		return true
+	}
+	// only the txs and claims are always retried by the client
+	if path != client.BlockTxsPath && path != client.ClaimsPath {
+		return false
+	}
+	key := path + "/" + strconv.FormatInt(params.Height, 10) + "/" + strconv.Itoa(params.Page)
+	n.requests[key]++
+	return n.requests[key] <= n.fixture.FailFirst
+}
+
+// Pages are numbered from 1, a page past the last one is empty
+func (n *Node) blockTxsPage(params client.PaginatedHeightParams) *coretypes.ResultTxSearch {
+	txs := n.txs[params.Height]
+	perPage := params.PerPage
+	if perPage <= 0 || (n.fixture.MaxPerPage > 0 && perPage > n.fixture.MaxPerPage) {
+		perPage = n.fixture.MaxPerPage
+	}
+	if perPage <= 0 {
+		perPage = len(txs)
+	}
+	page := params.Page
+	if page < 1 {
+		page = 1
+	}
+	start, end := (page-1)*perPage, page*perPage
+	if start > len(txs) {
+		start = len(txs)
+	}
+	if end > len(txs) {
+		end = len(txs)
+	}
+	result := &coretypes.ResultTxSearch{Txs: txs[start:end], TotalCount: end - start}
+	if result.Txs == nil {
+		result.Txs = make([]*coretypes.ResultTx, 0)
+	}
+	return result
+}
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/pokt-network/relay_counter/logging"
+)
+
+const (
+	// prefix of the environment variables overriding the config, e.g. RELAY_COUNTER_HTTP_RETRY
+	EnvPrefix         = "RELAY_COUNTER_"
+	EnvConfigFile     = EnvPrefix + "CONFIG"
+	DefaultConfigFile = "config/config.json"
+)
+
+const (
+	kindString = "string"
+	kindInt    = "integer"
+	kindList   = "list"
+	kindBool   = "boolean"
+)
+
+// A key of the config file that can also be set by an environment variable and a flag
+type configKey struct {
+	path  string // dotted path of the key in the config file
+	flag  string
+	kind  string
+	usage string
+}
+
+var configKeys = []configKey{
+	{"profile", "profile", kindString, "named profile applied over the keys of the config file: mainnet, testnet, localnet or a profile of the file"},
+	{"selector", "selector", kindString, "method used to select the blocks. It can be: timeline (default) or byBlock"},
+	{"timeline.start", "timelineStart", kindInt, "start of the timeline, counted back from the latest block"},
+	{"timeline.end", "timelineEnd", kindInt, "end of the timeline, counted back from the latest block, 0 for the latest"},
+	{"timeline.unit", "timelineUnit", kindString, "unit of the timeline: blocks, sessions, minutes, hours, days or weeks"},
+	{"byBlock.start", "startBlock", kindInt, "first height of the byBlock range"},
+	{"byBlock.end", "endBlock", kindInt, "height the byBlock range ends before"},
+	{"endpoint", "endpoint", kindString, "pocket-core version endpoint, ending in /v1"},
+	{"http_retry", "httpRetry", kindInt, "how many times a failed request is retried"},
+	{"params.blocks_per_session", "blocksPerSession", kindInt, "blocks in a session"},
+	{"params.approx_block_time_in_min", "blockTimeInMin", kindInt, "approximate time between blocks in minutes"},
+	{"filters.nodes", "nodes", kindList, "only report these comma separated node addresses"},
+	{"filters.nodes_file", "nodesFile", kindString, "file with one node address per line"},
+	{"filters.apps", "apps", kindList, "only report these comma separated app addresses or public keys"},
+	{"filters.apps_file", "appsFile", kindString, "file with one app address or public key per line"},
+	{"filters.chains", "chains", kindList, "only report these comma separated relay chain ids"},
+	{"filters.chains_file", "chainsFile", kindString, "file with one relay chain id per line"},
+	{"batch.split", "split", kindInt, "generates a report per range of this many units of the timeline, or blocks for byBlock"},
+	{"batch.combined", "combined", kindBool, "also generates the report of all the ranges of the batch, e.g. -combined=true"},
+}
+
+// e.g. RELAY_COUNTER_BYBLOCK_START for byBlock.start
+func (k configKey) env() string {
+	return EnvPrefix + strings.ToUpper(strings.Replace(k.path, ".", "_", -1))
+}
+
+// Converts the value of an environment variable or a flag to the type of the key
+func (k configKey) parse(value string) (interface{}, error) {
+	switch k.kind {
+	case kindInt:
+		return strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+	case kindList:
+		return splitList(value), nil
+	case kindBool:
+		return strconv.ParseBool(strings.TrimSpace(value))
+	default:
+		return value, nil
+	}
+}
+
+// The flags shared by every command to read the config file and override it
+type configFlags struct {
+	fs             *flag.FlagSet
+	configFilePath *string
+	values         map[string]*string
+	// the config file read by load, empty if there was none
+	file string
+}
+
+func newConfigFlags(fs *flag.FlagSet) *configFlags {
+	cf := &configFlags{
+		fs:             fs,
+		configFilePath: fs.String("config", DefaultConfigFile, "config file path, .json, .yaml, .yml or .toml. Env: "+EnvConfigFile),
+		values:         make(map[string]*string),
+	}
+	for _, k := range configKeys {
+		// the kind is shown as the flag type by -h
+		cf.values[k.path] = fs.String(k.flag, "", fmt.Sprintf("%s, sets %s (`%s`). Env: %s", k.usage, k.path, k.kind, k.env()))
+	}
+	return cf
+}
+
+// Builds the config from the defaults, the config file, its selected profile, the environment variables and the flags,
+// each one overriding the keys set by the previous ones, then validates it
+func (cf *configFlags) load() (Config, error) {
+	defaults, err := toConfigMap(DefaultConfig())
+	if err != nil {
+		return Config{}, err
+	}
+
+	cf.file = *cf.configFilePath
+	explicit := isFlagPassed(cf.fs, "config")
+	if env, ok := os.LookupEnv(EnvConfigFile); ok && !explicit {
+		cf.file, explicit = env, true
+	}
+	logging.Debug("Reading the config file", "file", cf.file)
+	fileLayer, err := readConfigFile(cf.file)
+	switch {
+	case os.IsNotExist(err) && !explicit:
+		logging.Info("No config file, using the defaults, environment variables and flags", "file", cf.file)
+		cf.file = ""
+	case err != nil:
+		return Config{}, err
+	}
+	// the profiles are applied as layers of their own
+	fileProfiles, _ := fileLayer["profiles"].(map[string]interface{})
+	if _, ok := fileLayer["profiles"]; ok && fileProfiles == nil {
+		return Config{}, &ConfigError{File: cf.source(), Errors: []error{NewInvalidProfilesError()}}
+	}
+	delete(fileLayer, "profiles")
+
+	var errs []error
+	envLayer, flagLayer := make(map[string]interface{}), make(map[string]interface{})
+	for _, k := range configKeys {
+		if value, ok := os.LookupEnv(k.env()); ok {
+			v, err := k.parse(value)
+			if err != nil {
+				errs = append(errs, NewInvalidConfigValueError(k.env(), value, k.kind))
+			}
+			setConfigKey(envLayer, k.path, v)
+		}
+		if isFlagPassed(cf.fs, k.flag) {
+			value := *cf.values[k.path]
+			v, err := k.parse(value)
+			if err != nil {
+				errs = append(errs, NewInvalidConfigValueError("-"+k.flag, value, k.kind))
+			}
+			setConfigKey(flagLayer, k.path, v)
+		}
+	}
+	if len(errs) != 0 {
+		return Config{}, &ConfigError{File: cf.source(), Errors: errs}
+	}
+	var builtinProfile, fileProfile map[string]interface{}
+	if profile := selectedProfile(fileLayer, envLayer, flagLayer); profile != "" {
+		logging.Debug("Applying the profile", "profile", profile)
+		if builtinProfile, fileProfile, err = profileLayers(profile, fileProfiles); err != nil {
+			return Config{}, &ConfigError{File: cf.source(), Errors: []error{err}}
+		}
+	}
+	// the profile goes over the top level keys of the file, the profile of the file extending the built-in one
+	c, err := mergeConfig(defaults, fileLayer, builtinProfile, fileProfile, envLayer, flagLayer)
+	if err != nil {
+		return c, err
+	}
+	logging.Debug("Config processed", "config", fmt.Sprintf("%+v", c))
+	if errs := c.Validate(); len(errs) != 0 {
+		return c, &ConfigError{File: cf.source(), Errors: errs}
+	}
+	return c, nil
+}
+
+// Where the config came from, for the errors
+func (cf *configFlags) source() string {
+	if cf.file == "" {
+		return "(defaults, environment and flags)"
+	}
+	return cf.file
+}
+
+func isFlagPassed(fs *flag.FlagSet, name string) (found bool) {
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			found = true
+		}
+	})
+	return
+}
@@ -3,11 +3,17 @@ module github.com/pokt-network/relay_counter
 go 1.13
 
 require (
+	github.com/BurntSushi/toml v0.3.1
 	github.com/dgraph-io/badger/v2 v2.2007.2 // indirect
+	github.com/mattn/go-sqlite3 v1.14.6
 	github.com/mitchellh/go-ps v1.0.0 // indirect
 	github.com/pokt-network/pocket-core v0.0.0-20210429190449-f794bc74b167
+	github.com/prometheus/client_golang v1.5.1
+	github.com/robfig/cron/v3 v3.0.1
 	github.com/tendermint/go-amino v0.15.0 // indirect
 	github.com/tendermint/tendermint v0.33.7
+	github.com/xeipuuv/gojsonschema v1.2.0
+	gopkg.in/yaml.v3 v3.0.0-20200313102051-9f266ea9e77c
 )
 
 replace github.com/tendermint/tendermint => github.com/pokt-network/tendermint v0.32.11-0.20210427155510-04e1c67f3eed // indirect
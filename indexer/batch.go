@@ -0,0 +1,168 @@
+package indexer
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/pokt-network/relay_counter/client"
+	"github.com/pokt-network/relay_counter/logging"
+	"github.com/pokt-network/relay_counter/report"
+	"github.com/pokt-network/relay_counter/selector"
+)
+
+const (
+	SelectorBatch = "batch"
+)
+
+// A range of a batch, named after its heights when Name is empty
+type BatchRange struct {
+	Name  string
+	Range selector.Options
+}
+
+type BatchReport struct {
+	Name   string
+	Report report.Report
+}
+
+// Resolves the ranges, retrieves the chain data of their union once and processes it into a report per range, in the order
+// of the ranges. When some heights, supplies or block times fail the reports of the ranges holding them are marked incomplete and an
+// *report.IncompleteError listing all of them is returned. An interrupted batch cuts short the range it stopped in and
+// leaves out the ranges after it
+func GenerateBatch(ctx context.Context, c *client.Client, opts Options, ranges []BatchRange) (results []BatchReport, err error) {
+	rangeOpts := make([]selector.Options, 0, len(ranges))
+	for _, r := range ranges {
+		rangeOpts = append(rangeOpts, r.Range)
+	}
+	blockReports, err := selector.SelectAll(ctx, c, rangeOpts)
+	if err != nil {
+		return nil, err
+	}
+	spans := unionSpans(blockReports)
+	logging.Info("Retrieving the batch", "ranges", len(ranges), "spans", len(spans))
+	blockTxsMap, claimsMap := make(BlockTxsMap), make(ClaimsMap)
+	var failed []report.HeightError
+	for _, span := range spans {
+		txs, claims, spanFailed := GetBlockData(ctx, c, span.MinHeight, span.MaxHeight)
+		for height, t := range txs {
+			blockTxsMap[height] = t
+		}
+		for height, cl := range claims {
+			claimsMap[height] = cl
+		}
+		failed = append(failed, spanFailed...)
+		if _, ok := report.CancelledHeight(spanFailed); ok {
+			break
+		}
+	}
+	cancelledHeight, cancelled := report.CancelledHeight(failed)
+	if ctx.Err() != nil {
+		var cancel context.CancelFunc
+		ctx, cancel = FinishContext(ctx)
+		defer cancel()
+	}
+	supplies := make(map[int64]int)
+	supplyFailed := make(map[int64]report.HeightError)
+	// the supplies and blocks that failed, shared failures are reported once
+	rangesFailed := make(map[report.HeightError]bool)
+	for i, r := range ranges {
+		blockReport := blockReports[i]
+		if cancelled && blockReport.MinHeight >= cancelledHeight {
+			logging.Info("Interrupted, the range is left out", "min_height", blockReport.MinHeight, "max_height", blockReport.MaxHeight)
+			continue
+		}
+		if cancelled && blockReport.MaxHeight > cancelledHeight {
+			logging.Info("Interrupted, the range is cut short", "height", cancelledHeight)
+			blockReport.MaxHeight = cancelledHeight
+		}
+		name := r.Name
+		if name == "" {
+			name = fmt.Sprintf("%d-%d", blockReport.MinHeight, blockReport.MaxHeight)
+		}
+		result, rangeFailed := processRange(ctx, c, opts, r.Range.Selector, blockReport, blockTxsMap, claimsMap, failed, supplies, supplyFailed)
+		for _, f := range rangeFailed {
+			if !rangesFailed[f] {
+				rangesFailed[f] = true
+				failed = append(failed, f)
+			}
+		}
+		results = append(results, BatchReport{Name: name, Report: result})
+	}
+	if len(failed) != 0 {
+		sort.SliceStable(failed, func(i, j int) bool { return failed[i].Height < failed[j].Height })
+		return results, &report.IncompleteError{FailedHeights: failed}
+	}
+	return results, nil
+}
+
+// Processes the chain data of the heights of the range into its report, the supplies and the supplies that failed are
+// shared by the ranges. Returns the supplies and blocks of the metadata that failed, the report holds them as well
+func processRange(ctx context.Context, c *client.Client, opts Options, selectorName string, blockReport report.BlockReport,
+	blockTxsMap BlockTxsMap, claimsMap ClaimsMap, failed []report.HeightError, supplies map[int64]int,
+	supplyFailed map[int64]report.HeightError) (result report.Report, rangeFailed []report.HeightError) {
+	for _, height := range []int64{blockReport.MinHeight - 1, blockReport.MaxHeight - 1} {
+		if f, ok := supplyFailed[height]; ok {
+			rangeFailed = append(rangeFailed, f)
+			continue
+		}
+		if _, ok := supplies[height]; ok {
+			continue
+		}
+		supply, err := getSupply(ctx, c, height)
+		if err != nil {
+			logging.Error("Unable to get the supply", "height", height, "err", err.Error())
+			supplyFailed[height] = report.NewHeightError(height, report.PhaseSupply, err)
+			rangeFailed = append(rangeFailed, supplyFailed[height])
+			continue
+		}
+		supplies[height] = supply
+	}
+	rangeTxs := make(BlockTxsMap, blockReport.MaxHeight-blockReport.MinHeight)
+	rangeClaims := make(ClaimsMap, blockReport.MaxHeight-blockReport.MinHeight)
+	var heightsFailed []report.HeightError
+	for height := blockReport.MinHeight; height < blockReport.MaxHeight; height++ {
+		if txs, ok := blockTxsMap[height]; ok {
+			rangeTxs[height] = txs
+		}
+		if claims, ok := claimsMap[height]; ok {
+			rangeClaims[height] = claims
+		}
+	}
+	for _, f := range failed {
+		// the cancelled height is past the end of the ranges cut short
+		if f.Height >= blockReport.MinHeight && f.Height < blockReport.MaxHeight {
+			heightsFailed = append(heightsFailed, f)
+		}
+	}
+	data := ChainData{BlockTxs: rangeTxs, Claims: rangeClaims}
+	// the range has no minted total when one of its supplies failed
+	if len(rangeFailed) == 0 {
+		data.SupplyStart, data.SupplyEnd = supplies[blockReport.MinHeight-1], supplies[blockReport.MaxHeight-1]
+	}
+	result = ProcessChainData(
+		data,
+		ProcessOptions{Selector: selectorName, BlockReport: blockReport, Filter: opts.Filter, Groups: opts.Groups},
+	)
+	metadata, metadataFailed := NewRunMetadata(ctx, c, blockReport, opts)
+	result.Metadata = &metadata
+	rangeFailed = append(rangeFailed, metadataFailed...)
+	report.AddFailedHeights(&result, append(heightsFailed, rangeFailed...))
+	return result, rangeFailed
+}
+
+// The sorted ranges covering the heights of all the block ranges, overlapping and adjacent ranges are joined
+func unionSpans(blockReports []report.BlockReport) (spans []report.BlockReport) {
+	sorted := append([]report.BlockReport(nil), blockReports...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].MinHeight < sorted[j].MinHeight })
+	for _, r := range sorted {
+		if last := len(spans) - 1; last >= 0 && r.MinHeight <= spans[last].MaxHeight {
+			if r.MaxHeight > spans[last].MaxHeight {
+				spans[last].MaxHeight = r.MaxHeight
+			}
+			continue
+		}
+		spans = append(spans, r)
+	}
+	return spans
+}
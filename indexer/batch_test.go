@@ -0,0 +1,41 @@
+package indexer
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/pokt-network/relay_counter/report"
+)
+
+func TestUnionSpans(t *testing.T) {
+	r := func(min, max int64) report.BlockReport { return report.BlockReport{MinHeight: min, MaxHeight: max} }
+	tests := []struct {
+		name     string
+		ranges   []report.BlockReport
+		expected []report.BlockReport
+	}{
+		{name: "no range"},
+		{name: "a single range", ranges: []report.BlockReport{r(10, 20)}, expected: []report.BlockReport{r(10, 20)}},
+		{name: "adjacent ranges are joined", ranges: []report.BlockReport{r(20, 30), r(10, 20)}, expected: []report.BlockReport{r(10, 30)}},
+		{name: "overlapping ranges are joined", ranges: []report.BlockReport{r(10, 25), r(20, 30)}, expected: []report.BlockReport{r(10, 30)}},
+		{name: "a range inside another", ranges: []report.BlockReport{r(10, 40), r(20, 30)}, expected: []report.BlockReport{r(10, 40)}},
+		{name: "identical ranges", ranges: []report.BlockReport{r(10, 20), r(10, 20)}, expected: []report.BlockReport{r(10, 20)}},
+		{
+			name:     "a gap keeps the ranges apart",
+			ranges:   []report.BlockReport{r(30, 40), r(10, 20), r(15, 25)},
+			expected: []report.BlockReport{r(10, 25), r(30, 40)},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			input := append([]report.BlockReport(nil), tt.ranges...)
+			if got := unionSpans(tt.ranges); !reflect.DeepEqual(got, tt.expected) {
+				t.Fatalf("expected %+v, got %+v", tt.expected, got)
+			}
+			if !reflect.DeepEqual(input, tt.ranges) {
+				t.Fatalf("the ranges were modified: %+v", tt.ranges)
+			}
+		})
+	}
+}
@@ -0,0 +1,163 @@
+package indexer
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/pokt-network/pocket-core/app/cmd/rpc"
+	pcTypes "github.com/pokt-network/pocket-core/x/pocketcore/types"
+	"github.com/pokt-network/relay_counter/client"
+	"github.com/pokt-network/relay_counter/report"
+)
+
+const (
+	// applied when the cache is created with a size of 0 or less
+	DefaultCacheEntries = 4096
+)
+
+// Caches the block-txs and claims and the supply of the heights fetched, up to maxEntries of them, evicting the least
+// recently used. The lock is not held while fetching, the requests for a height being fetched wait for that fetch
+type ChainDataCache struct {
+	client     *client.Client
+	maxEntries int
+	mu         sync.Mutex
+	entries    map[cacheKey]*list.Element
+	lru        *list.List
+	inflight   map[cacheKey]*cacheFetch
+}
+
+type cacheKey struct {
+	supply bool
+	height int64
+}
+
+type cacheEntry struct {
+	key   cacheKey
+	value interface{}
+}
+
+type blockData struct {
+	txs    rpc.RPCResultTxSearch
+	claims []pcTypes.MsgClaim
+}
+
+type cacheFetch struct {
+	done  chan struct{}
+	value interface{}
+	err   error
+}
+
+func NewChainDataCache(c *client.Client, maxEntries int) *ChainDataCache {
+	if maxEntries <= 0 {
+		maxEntries = DefaultCacheEntries
+	}
+	return &ChainDataCache{
+		client:     c,
+		maxEntries: maxEntries,
+		entries:    make(map[cacheKey]*list.Element),
+		lru:        list.New(),
+		inflight:   make(map[cacheKey]*cacheFetch),
+	}
+}
+
+// Returns the chain data of the heights from minHeight up to (not including) maxHeight, only fetching the missing heights.
+// The heights and supplies that fail are returned in an *report.IncompleteError along with the data of the others, both
+// supplies are 0 when one of them fails
+func (cache *ChainDataCache) Get(ctx context.Context, minHeight, maxHeight int64) (blockTxsMap BlockTxsMap, claimsMap ClaimsMap, supplyStart, supplyEnd int, err error) {
+	blockTxsMap = make(BlockTxsMap, maxHeight-minHeight)
+	claimsMap = make(ClaimsMap, maxHeight-minHeight)
+	var failed []report.HeightError
+	for height := minHeight; height < maxHeight; height++ {
+		h := height
+		value, err := cache.get(ctx, cacheKey{height: h}, func() (interface{}, error) {
+			txs, claims, heightFailed := GetBlockData(ctx, cache.client, h, h+1)
+			if len(heightFailed) != 0 {
+				return nil, &report.IncompleteError{FailedHeights: heightFailed}
+			}
+			return blockData{txs: txs[h], claims: claims[h]}, nil
+		})
+		// failed heights are not cached, so the next request retries them
+		if err != nil {
+			var incompleteErr *report.IncompleteError
+			if errors.As(err, &incompleteErr) {
+				failed = append(failed, incompleteErr.FailedHeights...)
+			} else {
+				failed = append(failed, report.NewHeightError(h, report.PhaseCancelled, err))
+			}
+			continue
+		}
+		data := value.(blockData)
+		blockTxsMap[height] = data.txs
+		claimsMap[height] = data.claims
+	}
+	var startErr, endErr error
+	if supplyStart, startErr = cache.getSupply(ctx, minHeight-1); startErr != nil {
+		failed = append(failed, report.NewHeightError(minHeight-1, report.PhaseSupply, startErr))
+	}
+	if supplyEnd, endErr = cache.getSupply(ctx, maxHeight-1); endErr != nil {
+		failed = append(failed, report.NewHeightError(maxHeight-1, report.PhaseSupply, endErr))
+	}
+	if startErr != nil || endErr != nil {
+		supplyStart, supplyEnd = 0, 0
+	}
+	if len(failed) != 0 {
+		return blockTxsMap, claimsMap, supplyStart, supplyEnd, &report.IncompleteError{FailedHeights: failed}
+	}
+	return blockTxsMap, claimsMap, supplyStart, supplyEnd, nil
+}
+
+func (cache *ChainDataCache) getSupply(ctx context.Context, height int64) (int, error) {
+	value, err := cache.get(ctx, cacheKey{supply: true, height: height}, func() (interface{}, error) {
+		return getSupply(ctx, cache.client, height)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return value.(int), nil
+}
+
+// Returns the cached value of key, or fetches it once whatever the number of concurrent requests for it. A request
+// waiting for the fetch of another one stops when its own ctx is cancelled
+func (cache *ChainDataCache) get(ctx context.Context, key cacheKey, fetch func() (interface{}, error)) (interface{}, error) {
+	cache.mu.Lock()
+	if elem, ok := cache.entries[key]; ok {
+		cache.lru.MoveToFront(elem)
+		cache.mu.Unlock()
+		return elem.Value.(*cacheEntry).value, nil
+	}
+	if f, ok := cache.inflight[key]; ok {
+		cache.mu.Unlock()
+		select {
+		case <-f.done:
+			return f.value, f.err
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	f := &cacheFetch{done: make(chan struct{})}
+	cache.inflight[key] = f
+	cache.mu.Unlock()
+
+	f.value, f.err = fetch()
+
+	cache.mu.Lock()
+	delete(cache.inflight, key)
+	if f.err == nil {
+		cache.entries[key] = cache.lru.PushFront(&cacheEntry{key: key, value: f.value})
+		cache.evict()
+	}
+	cache.mu.Unlock()
+	close(f.done)
+	return f.value, f.err
+}
+
+// Drops the least recently used entries above the size of the cache. Called with the lock held
+func (cache *ChainDataCache) evict() {
+	for cache.lru.Len() > cache.maxEntries {
+		elem := cache.lru.Back()
+		cache.lru.Remove(elem)
+		delete(cache.entries, elem.Value.(*cacheEntry).key)
+	}
+}
@@ -0,0 +1,33 @@
+package indexer
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	FinishTimeout = 30 * time.Second
+)
+
+// Returns a context that is not cancelled with ctx, limited to FinishTimeout, for the few requests
+// an interrupted run still makes to write its partial report
+func FinishContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(detachedContext{ctx}, FinishTimeout)
+}
+
+// Keeps the values of the parent context but not its cancellation
+type detachedContext struct {
+	context.Context
+}
+
+func (detachedContext) Deadline() (deadline time.Time, ok bool) {
+	return
+}
+
+func (detachedContext) Done() <-chan struct{} {
+	return nil
+}
+
+func (detachedContext) Err() error {
+	return nil
+}
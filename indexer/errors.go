@@ -0,0 +1,17 @@
+package indexer
+
+import (
+	"fmt"
+)
+
+func NewProofMsgInterfaceError() error {
+	return fmt.Errorf("ERROR: unable to convert interface to ProofMsg")
+}
+
+func NewMissingClaimError(signer string) error {
+	return fmt.Errorf("ERROR: no claim for valid proof object of %s", signer)
+}
+
+func NewSupplyError(height int64, err error) error {
+	return fmt.Errorf("ERROR: unable to get the supply at height: %d with error %s", height, err.Error())
+}
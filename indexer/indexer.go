@@ -0,0 +1,394 @@
+// Package indexer retrieves the transactions and claims of a block range and processes them into a report.
+package indexer
+
+import (
+	"context"
+	"time"
+
+	"github.com/pokt-network/pocket-core/app/cmd/rpc"
+	pcTypes "github.com/pokt-network/pocket-core/x/pocketcore/types"
+	"github.com/pokt-network/relay_counter/client"
+	"github.com/pokt-network/relay_counter/logging"
+	"github.com/pokt-network/relay_counter/progress"
+	"github.com/pokt-network/relay_counter/report"
+	"github.com/pokt-network/relay_counter/selector"
+	coretypes "github.com/tendermint/tendermint/rpc/core/types"
+)
+
+type ClaimsMap map[int64][]pcTypes.MsgClaim
+type BlockTxsMap map[int64]rpc.RPCResultTxSearch
+
+// What GenerateReport indexes: the block range selection, the filter and groups the report is scoped to
+// and the hash and profile of the config recorded in the metadata
+type Options struct {
+	Range      selector.Options
+	Filter     report.Filter
+	Groups     report.Groups
+	ConfigHash string
+	Profile    string
+}
+
+// Selects the block range, retrieves the chain data and processes it into a report
+func GenerateReport(ctx context.Context, c *client.Client, opts Options) (result report.Report, err error) {
+	blockReport, err := selector.Select(ctx, c, opts.Range)
+	if err != nil {
+		return result, err
+	}
+	blockTxsMap, claimsMap, startSupply, endSupply, failed := GetChainData(ctx, c, blockReport.MinHeight, blockReport.MaxHeight)
+	if height, ok := report.CancelledHeight(failed); ok {
+		logging.Info("Interrupted, the report is cut short", "height", height)
+		blockReport.MaxHeight = height
+	}
+	if ctx.Err() != nil {
+		var cancel context.CancelFunc
+		ctx, cancel = FinishContext(ctx)
+		defer cancel()
+	}
+	result = ProcessChainData(
+		ChainData{BlockTxs: blockTxsMap, Claims: claimsMap, SupplyStart: startSupply, SupplyEnd: endSupply},
+		ProcessOptions{Selector: opts.Range.Selector, BlockReport: blockReport, Filter: opts.Filter, Groups: opts.Groups},
+	)
+	report.AddFailedHeights(&result, failed)
+	logging.Debug("Getting the run metadata")
+	metadata, metadataFailed := NewRunMetadata(ctx, c, blockReport, opts)
+	result.Metadata = &metadata
+	report.AddFailedHeights(&result, metadataFailed)
+	if result.Incomplete {
+		return result, &report.IncompleteError{FailedHeights: result.FailedHeights}
+	}
+	return result, nil
+}
+
+// Retrieves the block-txs, claims and supplies of the range, the heights and supplies that could not be retrieved are
+// returned in failed. When a supply fails both are left at 0, so the report has no minted total
+func GetChainData(ctx context.Context, c *client.Client, minHeight, maxHeight int64) (blockTxsMap BlockTxsMap, claimsMap ClaimsMap, supplyStart, supplyEnd int, failed []report.HeightError) {
+	blockTxsMap, claimsMap, failed = GetBlockData(ctx, c, minHeight, maxHeight)
+	// an interrupted run still gets the supplies of the heights retrieved, so its partial report is consistent
+	if height, ok := report.CancelledHeight(failed); ok {
+		maxHeight = height
+	}
+	if ctx.Err() != nil {
+		var cancel context.CancelFunc
+		ctx, cancel = FinishContext(ctx)
+		defer cancel()
+	}
+	// get the beginning and end supply
+	var startErr, endErr error
+	if supplyStart, startErr = getSupply(ctx, c, minHeight-1); startErr != nil {
+		logging.Error("Unable to get the supply", "height", minHeight-1, "err", startErr.Error())
+		failed = append(failed, report.NewHeightError(minHeight-1, report.PhaseSupply, startErr))
+	}
+	if supplyEnd, endErr = getSupply(ctx, c, maxHeight-1); endErr != nil {
+		logging.Error("Unable to get the supply", "height", maxHeight-1, "err", endErr.Error())
+		failed = append(failed, report.NewHeightError(maxHeight-1, report.PhaseSupply, endErr))
+	}
+	if startErr != nil || endErr != nil {
+		return blockTxsMap, claimsMap, 0, 0, failed
+	}
+	logging.Info("Retrieved the supplies", "start", supplyStart, "end", supplyEnd)
+	return
+}
+
+func getSupply(ctx context.Context, c *client.Client, height int64) (supply int, err error) {
+	logging.Debug("Getting the supply", "height", height)
+	err = c.Retry(ctx, "supply", 5*time.Second, func() (err error) {
+		supply, err = c.GetSupply(ctx, height)
+		return
+	})
+	if err != nil {
+		return 0, NewSupplyError(height, err)
+	}
+	return supply, nil
+}
+
+// Retrieves the block-txs and claims of the heights from minHeight up to (not including) maxHeight.
+// A height that fails after the retries is returned in failed and left out of the maps, the other heights are still retrieved.
+// When ctx is cancelled the retrieval stops, the height it stopped at is returned in failed with the cancelled phase
+func GetBlockData(ctx context.Context, c *client.Client, minHeight, maxHeight int64) (blockTxsMap BlockTxsMap, claimsMap ClaimsMap, failed []report.HeightError) {
+	blockTxsMap = make(BlockTxsMap, 0)
+	claimsMap = make(ClaimsMap, 0)
+	// loop through all the heights and retrieve all the block-txs
+	logging.Info("Retrieving the transactions and claims", "min_height", minHeight, "max_height", maxHeight)
+	txsProgress := progress.Start(progress.PhaseBlockTxs, maxHeight-minHeight)
+	defer txsProgress.Done()
+	claimsProgress := progress.Start(progress.PhaseClaims, maxHeight-minHeight)
+	defer claimsProgress.Done()
+	for height := minHeight; height < maxHeight; height++ {
+		if ctx.Err() != nil {
+			return cancelAt(ctx, height, blockTxsMap, claimsMap, failed)
+		}
+		cur := rpc.RPCResultTxSearch{}
+		for page := 1; ; page++ {
+			var result rpc.RPCResultTxSearch
+			err := c.Retry(ctx, "blocktxs", 1*time.Second, txsProgress.Attempts(func() (err error) {
+				result, err = c.GetBlockTx(ctx, height, page)
+				return
+			}))
+			if err != nil && ctx.Err() != nil {
+				return cancelAt(ctx, height, blockTxsMap, claimsMap, failed)
+			}
+			if err != nil {
+				logging.Error("Unable to get the block-txs", "height", height, "page", page, "err", err.Error())
+				failed = append(failed, report.NewHeightError(height, report.PhaseBlockTxs, err))
+				break
+			}
+			if result.TotalCount == 0 {
+				blockTxsMap[height] = cur
+				logging.Debug("Retrieved the block-txs", "height", height, "pages", page-1, "txs", len(cur.Txs))
+				break
+			}
+			cur.TotalCount += result.TotalCount
+			cur.Txs = append(cur.Txs, result.Txs...)
+		}
+		txsProgress.Add(1)
+		// skip claims for blocks 0 and 1
+		if height == 0 || height == 1 {
+			claimsProgress.Add(1)
+			logHeight(height, minHeight, maxHeight, blockTxsMap, nil)
+			continue
+		}
+		// we want to check the claim at height - 1 cause the state = endBlockState
+		var claimsResult []pcTypes.MsgClaim
+		err := c.Retry(ctx, "claims", 5*time.Second, claimsProgress.Attempts(func() (err error) {
+			claimsResult, err = c.GetClaims(ctx, height-1)
+			return
+		}))
+		if err != nil && ctx.Err() != nil {
+			return cancelAt(ctx, height, blockTxsMap, claimsMap, failed)
+		}
+		if err != nil {
+			logging.Error("Unable to get the claims", "height", height, "err", err.Error())
+			failed = append(failed, report.NewHeightError(height, report.PhaseClaims, err))
+			claimsProgress.Add(1)
+			continue
+		}
+		// add the claims to the result
+		claimsMap[height] = claimsResult
+		claimsProgress.Add(1)
+		logHeight(height, minHeight, maxHeight, blockTxsMap, claimsResult)
+	}
+	logging.Info("Retrieved the transactions and claims", "heights", maxHeight-minHeight, "failed", len(failed))
+	return
+}
+
+// Logs the progress of a height whose block-txs and claims were both retrieved
+func logHeight(height, minHeight, maxHeight int64, blockTxsMap BlockTxsMap, claims []pcTypes.MsgClaim) {
+	blockTxs, ok := blockTxsMap[height]
+	if !ok {
+		return
+	}
+	logging.Info("Retrieved height", "height", height, "txs", len(blockTxs.Txs), "claims", len(claims),
+		"done", height-minHeight+1, "total", maxHeight-minHeight)
+}
+
+// Drops the partial data of the height the retrieval was interrupted at and marks it cancelled
+func cancelAt(ctx context.Context, height int64, blockTxsMap BlockTxsMap, claimsMap ClaimsMap, failed []report.HeightError) (BlockTxsMap, ClaimsMap, []report.HeightError) {
+	logging.Info("Retrieval interrupted", "height", height)
+	delete(blockTxsMap, height)
+	delete(claimsMap, height)
+	kept := failed[:0]
+	for _, f := range failed {
+		if f.Height != height {
+			kept = append(kept, f)
+		}
+	}
+	return blockTxsMap, claimsMap, append(kept, report.NewHeightError(height, report.PhaseCancelled, ctx.Err()))
+}
+
+// The retrieved chain data of a range
+type ChainData struct {
+	BlockTxs    BlockTxsMap
+	Claims      ClaimsMap
+	SupplyStart int
+	SupplyEnd   int
+}
+
+// How the chain data is processed: the selector and range recorded in the report, and the filter and groups of the claims
+type ProcessOptions struct {
+	Selector    string
+	BlockReport report.BlockReport
+	Filter      report.Filter
+	Groups      report.Groups
+}
+
+// Counts the txs, proofs and claims of the chain data into a report
+func ProcessChainData(data ChainData, opts ProcessOptions) (result report.Report) {
+	txsMap, claimsMap, filter, groups := data.BlockTxs, data.Claims, opts.Filter, opts.Groups
+	result = report.New(opts.Selector, opts.BlockReport)
+	if !filter.IsEmpty() {
+		logging.Info("Filters set, node and app reports are limited to the matching claims")
+		result.Filtered = report.NewFilteredReport(filter)
+	}
+	if len(groups) != 0 {
+		result.GroupReports = groups.NewGroupReports()
+	}
+	p := progress.Start(progress.PhaseProcessing, int64(len(txsMap)))
+	defer p.Done()
+	for height, blockTx := range txsMap {
+		for _, txResult := range blockTx.Txs {
+			// check if bad transaction
+			if txResult.TxResult.Code != 0 {
+				logging.Debug("Bad tx found", "height", height, "hash", txResult.Hash.String(), "code", txResult.TxResult.Code)
+				result.TotalBadTxs++
+				result.BadTxsMap[txResult.TxResult.Code]++
+				continue
+			}
+			// log good tx
+			result.TotalGoodTxs++
+			// if not proofTx, continue on
+			if txResult.StdTx.Msg.Type() != pcTypes.MsgProofName {
+				logging.Debug("Good non-proof tx found", "height", height, "hash", txResult.Hash.String())
+				continue
+			}
+			// this is a proof msg
+			proofMsg, ok := txResult.StdTx.Msg.(pcTypes.MsgProof)
+			if !ok {
+				logging.Error("Unable to process the proof tx", "height", height, "err", NewProofMsgInterfaceError().Error())
+				result.FailedHeights = append(result.FailedHeights, report.NewHeightError(height, report.PhaseProcess, NewProofMsgInterfaceError()))
+				continue
+			}
+			// log good tx
+			result.TotalProofTxs++
+			logging.Debug("Proof tx found", "height", height, "hash", txResult.Hash.String())
+			claim := pcTypes.MsgClaim{}
+			// find the corresponding claim
+			for _, c := range claimsMap[height] {
+				if !c.FromAddress.Equals(proofMsg.GetSigner()) {
+					continue
+				}
+				claim = c
+			}
+			if claim.FromAddress == nil {
+				logging.Error("No claim for the valid proof", "height", height, "node", proofMsg.GetSigner().String())
+				result.FailedHeights = append(result.FailedHeights, report.NewHeightError(height, report.PhaseProcess, NewMissingClaimError(proofMsg.GetSigner().String())))
+				continue
+			}
+			logging.Debug("Corresponding claim found", "height", height, "node", claim.FromAddress.String())
+			// get appAddress
+			appAddress, err := report.GetAddressFromPubKey(claim.SessionHeader.ApplicationPubKey)
+			if err != nil {
+				logging.Error("Unable to process the claim", "height", height, "err", err.Error())
+				result.FailedHeights = append(result.FailedHeights, report.NewHeightError(height, report.PhaseProcess, err))
+				continue
+			}
+			nodeAddress := claim.FromAddress.String()
+			// get total # of relays
+			totalRelays := claim.TotalProofs
+			// get the relay chain id
+			chainID := claim.SessionHeader.Chain
+			// check if the claim is within the filters
+			matched := filter.Match(nodeAddress, appAddress, chainID)
+			if matched && result.Filtered != nil {
+				result.Filtered.TotalProofTxs++
+			}
+			// the groups the node or app belong to
+			var groupNames []string
+			if matched {
+				groupNames = groups.Match(nodeAddress, appAddress)
+			}
+			// check to see if claim is for relays
+			et := claim.EvidenceType
+			if et != pcTypes.RelayEvidence {
+				result.TotalChallengesCompleted++
+				if matched && result.Filtered != nil {
+					result.Filtered.TotalChallengesCompleted++
+				}
+				for _, name := range groupNames {
+					groupReport := result.GroupReports[name]
+					groupReport.TotalChallengesCompleted++
+					result.GroupReports[name] = groupReport
+				}
+				continue
+			}
+			// network wide total
+			result.TotalRelaysCompleted += totalRelays
+			if !matched {
+				continue
+			}
+			if result.Filtered != nil {
+				result.Filtered.TotalRelaysCompleted += totalRelays
+				result.Filtered.RelaysByChain[chainID] += totalRelays
+			}
+			// retrieve the app/node reports
+			appReport, found := result.AppReports[appAddress]
+			if !found {
+				logging.Debug("New app report created", "address", appAddress)
+				appReport = report.NewAppReport()
+			}
+			nodeReport, found := result.NodeReports[nodeAddress]
+			if !found {
+				logging.Debug("New node report created", "address", nodeAddress)
+				nodeReport = report.NewNodeReport()
+			}
+			// add to the reports totals
+			appReport.TotalRelays += totalRelays
+			nodeReport.TotalRelays += totalRelays
+			// add to the chain statistics
+			appReport.ServicedReportByChain[chainID] += totalRelays
+			nodeReport.ServiceReportByChain[chainID] += totalRelays
+			// add an individual service report to the appReport
+			appReport.ServicedBy = append(appReport.ServicedBy, report.ServiceReport{
+				Address:     nodeAddress,
+				TotalRelays: totalRelays,
+				ChainID:     chainID,
+			})
+			// add an individual service report to the nodeReport
+			nodeReport.Service = append(nodeReport.Service, report.ServiceReport{
+				Address:     appAddress,
+				TotalRelays: totalRelays,
+				ChainID:     chainID,
+			})
+			// set the reports in the master report
+			result.AppReports[appAddress] = appReport
+			result.NodeReports[nodeAddress] = nodeReport
+			// add to the group totals
+			for _, name := range groupNames {
+				groupReport := result.GroupReports[name]
+				groupReport.TotalRelays += totalRelays
+				groupReport.ReportByChain[chainID] += totalRelays
+				result.GroupReports[name] = groupReport
+			}
+		}
+		p.Add(1)
+	}
+	report.AddFailedHeights(&result, nil)
+	// set the supply difference as total minted
+	result.TotalMinted = int64(data.SupplyEnd - data.SupplyStart)
+	logging.Info("Processed the chain data", "heights", len(txsMap), "good_txs", result.TotalGoodTxs, "bad_txs", result.TotalBadTxs,
+		"proof_txs", result.TotalProofTxs, "relays", result.TotalRelaysCompleted, "failed", len(result.FailedHeights))
+	return result
+}
+
+// Describes the run of the report, the block times are the times of the first and last heights of the range.
+// A block that can't be retrieved after the retries leaves its time unset and is returned in failed
+func NewRunMetadata(ctx context.Context, c *client.Client, blockReport report.BlockReport, opts Options) (metadata report.RunMetadata, failed []report.HeightError) {
+	metadata = report.RunMetadata{
+		ToolVersion: report.Version,
+		Endpoint:    c.Endpoint(),
+		Profile:     opts.Profile,
+		ConfigHash:  opts.ConfigHash,
+		GeneratedAt: time.Now().UTC(),
+	}
+	var err error
+	if metadata.StartBlockTime, err = getBlockTime(ctx, c, blockReport.MinHeight); err != nil {
+		failed = append(failed, report.NewHeightError(blockReport.MinHeight, report.PhaseBlock, err))
+	}
+	if metadata.EndBlockTime, err = getBlockTime(ctx, c, blockReport.MaxHeight-1); err != nil {
+		failed = append(failed, report.NewHeightError(blockReport.MaxHeight-1, report.PhaseBlock, err))
+	}
+	return metadata, failed
+}
+
+func getBlockTime(ctx context.Context, c *client.Client, height int64) (*time.Time, error) {
+	var block *coretypes.ResultBlock
+	err := c.Retry(ctx, "block", 5*time.Second, func() (err error) {
+		block, err = c.GetBlock(ctx, height)
+		return
+	})
+	if err != nil {
+		logging.Error("Unable to get the block", "height", height, "err", err.Error())
+		return nil, err
+	}
+	blockTime := block.Block.Time.UTC()
+	return &blockTime, nil
+}
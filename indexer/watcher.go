@@ -0,0 +1,127 @@
+package indexer
+
+import (
+	"context"
+
+	"github.com/pokt-network/relay_counter/client"
+	"github.com/pokt-network/relay_counter/logging"
+	"github.com/pokt-network/relay_counter/report"
+)
+
+const (
+	SelectorWatch = "watch"
+)
+
+// Indexes new blocks as they arrive, keeping a report per height of the rolling window only and a running count of
+// the relays. The range selection of the options is not used, the watcher starts at startHeight
+type Watcher struct {
+	client        *client.Client
+	opts          Options
+	windowBlocks  int64
+	heightReports map[int64]report.Report
+	startHeight   int64
+	nextHeight    int64
+	totalRelays   int64
+}
+
+func NewWatcher(c *client.Client, opts Options, windowBlocks, startHeight int64) *Watcher {
+	return &Watcher{
+		client:        c,
+		opts:          opts,
+		windowBlocks:  windowBlocks,
+		heightReports: make(map[int64]report.Report),
+		startHeight:   startHeight,
+		nextHeight:    startHeight,
+	}
+}
+
+// The height the next poll starts indexing at
+func (w *Watcher) NextHeight() int64 {
+	return w.nextHeight
+}
+
+// The relays indexed since the watcher started
+func (w *Watcher) TotalRelays() int64 {
+	return w.totalRelays
+}
+
+// Indexes every height up to (not including) the latest height. When ctx is cancelled it stops
+// before the height being indexed, which the next poll starts at, and returns the error of ctx
+func (w *Watcher) Poll(ctx context.Context) (indexed int, err error) {
+	latestHeight, err := w.client.GetLatestHeight(ctx)
+	if err != nil {
+		return 0, err
+	}
+	for ; w.nextHeight < latestHeight; w.nextHeight++ {
+		if !w.index(ctx, w.nextHeight) {
+			return indexed, ctx.Err()
+		}
+		indexed++
+	}
+	// drop the heights that left the window
+	for height := range w.heightReports {
+		if height < w.nextHeight-w.windowBlocks {
+			delete(w.heightReports, height)
+		}
+	}
+	return indexed, nil
+}
+
+// Returns false if the height was not indexed because ctx was cancelled
+func (w *Watcher) index(ctx context.Context, height int64) bool {
+	blockReport := report.BlockReport{MinHeight: height, MaxHeight: height + 1}
+	blockTxsMap, claimsMap, failed := GetBlockData(ctx, w.client, blockReport.MinHeight, blockReport.MaxHeight)
+	if _, ok := report.CancelledHeight(failed); ok {
+		return false
+	}
+	heightReport := ProcessChainData(
+		ChainData{BlockTxs: blockTxsMap, Claims: claimsMap},
+		ProcessOptions{Selector: SelectorWatch, BlockReport: blockReport, Filter: w.opts.Filter, Groups: w.opts.Groups},
+	)
+	report.AddFailedHeights(&heightReport, failed)
+	if heightReport.Incomplete {
+		logging.Error("Height is incomplete", "height", height, "err", (&report.IncompleteError{FailedHeights: heightReport.FailedHeights}).Error())
+	}
+	w.heightReports[height] = heightReport
+	w.totalRelays += heightReport.TotalRelaysCompleted
+	logging.Info("Height indexed", "height", height, "relays", heightReport.TotalRelaysCompleted, "total_relays", w.totalRelays,
+		"min_height", w.startHeight, "max_height", height+1)
+	return true
+}
+
+// Merges the reports of the heights in the window and sets the minted supply of the window. The report is marked
+// incomplete when the supplies or the block times of the window can't be retrieved
+func (w *Watcher) RollingReport(ctx context.Context) (result report.Report) {
+	minHeight := w.nextHeight - w.windowBlocks
+	if minHeight < w.startHeight {
+		minHeight = w.startHeight
+	}
+	reports := make([]report.Report, 0, w.windowBlocks)
+	for height := minHeight; height < w.nextHeight; height++ {
+		if heightReport, ok := w.heightReports[height]; ok {
+			reports = append(reports, heightReport)
+		}
+	}
+	result = report.Merge(reports...)
+	result.BlockSelector = SelectorWatch
+	result.BlockReport = report.BlockReport{MinHeight: minHeight, MaxHeight: w.nextHeight}
+	var failed []report.HeightError
+	supplyStart, err := getSupply(ctx, w.client, minHeight-1)
+	if err != nil {
+		failed = append(failed, report.NewHeightError(minHeight-1, report.PhaseSupply, err))
+	}
+	supplyEnd, err := getSupply(ctx, w.client, w.nextHeight-1)
+	if err != nil {
+		failed = append(failed, report.NewHeightError(w.nextHeight-1, report.PhaseSupply, err))
+	}
+	if len(failed) == 0 {
+		result.TotalMinted = int64(supplyEnd - supplyStart)
+	}
+	metadata, metadataFailed := NewRunMetadata(ctx, w.client, result.BlockReport, w.opts)
+	result.Metadata = &metadata
+	report.AddFailedHeights(&result, append(failed, metadataFailed...))
+	if result.Incomplete {
+		logging.Error("Rolling report is incomplete", "err", (&report.IncompleteError{FailedHeights: result.FailedHeights}).Error())
+	}
+	return result
+}
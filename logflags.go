@@ -0,0 +1,83 @@
+package main
+
+import (
+	"flag"
+	"io"
+	"os"
+	"time"
+
+	"github.com/pokt-network/relay_counter/logging"
+	"github.com/pokt-network/relay_counter/progress"
+)
+
+const (
+	ProgressAuto = "auto"
+	ProgressLive = "live"
+	ProgressLog  = "log"
+	ProgressOff  = "off"
+)
+
+// The flags shared by every command to choose how the logs, and the progress of the commands that report it, are written
+type logFlags struct {
+	format           *string
+	level            *string
+	progress         *string
+	progressInterval *time.Duration
+}
+
+func newLogFlags(fs *flag.FlagSet) *logFlags {
+	return &logFlags{
+		format: fs.String("logFormat", logging.FormatText, "format of the logs. It can be: text (default) or json"),
+		level:  fs.String("logLevel", logging.LevelInfo, "lowest level logged. It can be: debug (a line per tx), info (default, a line per height) or error"),
+	}
+}
+
+// Adds the flags of the progress reporter
+func (lf *logFlags) withProgress(fs *flag.FlagSet) *logFlags {
+	lf.progress = fs.String("progress", ProgressAuto, "how the progress is shown. It can be: auto (default, live on a terminal, log otherwise), live (a line redrawn in place), log (periodic log lines) or off")
+	lf.progressInterval = fs.Duration("progressInterval", 30*time.Second, "time between the progress log lines of a phase")
+	return lf
+}
+
+// Sets the logger of every package and the progress reporter, both go to stderr
+func (lf *logFlags) apply() {
+	var w io.Writer = os.Stderr
+	if lf.progress != nil {
+		mode := *lf.progress
+		if mode == ProgressAuto {
+			mode = ProgressLog
+			if isTerminal(os.Stderr) {
+				mode = ProgressLive
+			}
+		}
+		switch mode {
+		case ProgressLive:
+			r := progress.NewReporter(os.Stderr, true, *lf.progressInterval)
+			progress.SetReporter(r)
+			w = r.Writer()
+		case ProgressLog:
+			progress.SetReporter(progress.NewReporter(os.Stderr, false, *lf.progressInterval))
+		case ProgressOff:
+		default:
+			fatal(NewInvalidProgressError(*lf.progress))
+		}
+	}
+	logger, err := logging.New(w, *lf.format, *lf.level)
+	if err != nil {
+		fatal(err)
+	}
+	logging.SetLogger(logger)
+}
+
+// Logs the error, notifies the webhooks of the run and exits with status 1
+func fatal(err error) {
+	logging.Error(err.Error())
+	saveRecording()
+	notifyFailure(err)
+	os.Exit(1)
+}
+
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
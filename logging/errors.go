@@ -0,0 +1,13 @@
+package logging
+
+import (
+	"fmt"
+)
+
+func NewInvalidFormatError(format string) error {
+	return fmt.Errorf("ERROR: unrecognized log format: %s, valid formats: (text, json)", format)
+}
+
+func NewInvalidLevelError(level string) error {
+	return fmt.Errorf("ERROR: unrecognized log level: %s, valid levels: (debug, info, error)", level)
+}
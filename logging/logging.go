@@ -0,0 +1,88 @@
+// Package logging is the leveled logger shared by the packages, writing key-value lines as text or JSON.
+package logging
+
+import (
+	"io"
+	"os"
+	"time"
+
+	tmlog "github.com/tendermint/tendermint/libs/log"
+)
+
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+	LevelDebug = "debug"
+	LevelInfo  = "info"
+	LevelError = "error"
+)
+
+// text at info level on stderr until SetLogger is called
+var logger = tmlog.NewFilter(tmlog.NewTMLogger(tmlog.NewSyncWriter(os.Stderr)), tmlog.AllowInfo())
+
+// Returns a logger writing to w in the format, dropping the entries below the level
+func New(w io.Writer, format, level string) (tmlog.Logger, error) {
+	var l tmlog.Logger
+	switch format {
+	case FormatText:
+		l = tmlog.NewTMLogger(tmlog.NewSyncWriter(w))
+	case FormatJSON:
+		l = timestamped{tmlog.NewTMJSONLogger(tmlog.NewSyncWriter(w))}
+	default:
+		return nil, NewInvalidFormatError(format)
+	}
+	switch level {
+	case LevelDebug:
+		return tmlog.NewFilter(l, tmlog.AllowDebug()), nil
+	case LevelInfo:
+		return tmlog.NewFilter(l, tmlog.AllowInfo()), nil
+	case LevelError:
+		return tmlog.NewFilter(l, tmlog.AllowError()), nil
+	default:
+		return nil, NewInvalidLevelError(level)
+	}
+}
+
+// Replaces the logger of every package, meant to be called once at startup
+func SetLogger(l tmlog.Logger) {
+	logger = l
+}
+
+// Detail that is only useful when debugging, such as a line per tx
+func Debug(msg string, keyvals ...interface{}) {
+	logger.Debug(msg, keyvals...)
+}
+
+// Progress of the run, such as a line per height or per phase
+func Info(msg string, keyvals ...interface{}) {
+	logger.Info(msg, keyvals...)
+}
+
+func Error(msg string, keyvals ...interface{}) {
+	logger.Error(msg, keyvals...)
+}
+
+// Adds the time to the entries, the JSON logger of tendermint leaves it out
+type timestamped struct {
+	next tmlog.Logger
+}
+
+func (t timestamped) Debug(msg string, keyvals ...interface{}) {
+	t.next.Debug(msg, append([]interface{}{"ts", now()}, keyvals...)...)
+}
+
+func (t timestamped) Info(msg string, keyvals ...interface{}) {
+	t.next.Info(msg, append([]interface{}{"ts", now()}, keyvals...)...)
+}
+
+func (t timestamped) Error(msg string, keyvals ...interface{}) {
+	t.next.Error(msg, append([]interface{}{"ts", now()}, keyvals...)...)
+}
+
+func (t timestamped) With(keyvals ...interface{}) tmlog.Logger {
+	return timestamped{t.next.With(keyvals...)}
+}
+
+func now() string {
+	return time.Now().UTC().Format(time.RFC3339Nano)
+}
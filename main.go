@@ -2,77 +2,144 @@ package main
 
 import (
 	"flag"
-	"log"
+	"os"
 	"time"
-)
-
-func main() {
-	now := time.Now().AddDate(0, 0, -1).Format("01-02-06T15:04:05")
-	configFilePath := flag.String("config", "config/config.json", "config file path")
-	resultFilePath := flag.String("results", "result/"+now+".json", "results file path")
 
-	selector := flag.String("selector", "", "use this to point which method will you use to select block. It can be: timeline (default) or byBlock")
+	"github.com/pokt-network/relay_counter/indexer"
+	"github.com/pokt-network/relay_counter/logging"
+	"github.com/pokt-network/relay_counter/notify"
+	"github.com/pokt-network/relay_counter/report"
+)
 
-	timelineStart := flag.Int64("timelineStart", -99999, "override timeline.start.")
-	timelineEnd := flag.Int64("timelineEnd", -99999, "override timeline.end.")
-	timelineUnit := flag.String("timelineUnit", "", "override timeline.unit.")
+const (
+	// the command run without a command name, as named in the notifications
+	CommandReport   = "report"
+	CommandExporter = "exporter"
+)
 
-	startBlock := flag.Int64("startBlock", -99999, "override byBlock.start.")
-	endBlock := flag.Int64("endBlock", -99999, "override byBlock.end.")
+const (
+	// the report was written but some heights failed
+	ExitCodeIncomplete = 3
+	// the run was stopped by SIGINT or SIGTERM, the partial report was written if any heights were retrieved
+	ExitCodeInterrupted = 130
+)
 
-	// node
-	endpoint := flag.String("endpoint", "", "override endpoint.")
-	httpRetry := flag.Int("httpRetry", -1, "override http_retry.")
+func main() {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case CommandExporter:
+			runExporter(os.Args[2:])
+			return
+		case CommandWatch:
+			runWatch(os.Args[2:])
+			return
+		case CommandServe:
+			runServe(os.Args[2:])
+			return
+		case CommandDiff:
+			runDiff(os.Args[2:])
+			return
+		case CommandMerge:
+			runMerge(os.Args[2:])
+			return
+		case CommandSchema:
+			runSchema(os.Args[2:])
+			return
+		case CommandValidate:
+			runValidate(os.Args[2:])
+			return
+		case CommandSchedule:
+			runSchedule(os.Args[2:])
+			return
+		}
+	}
+	runReport(os.Args[1:])
+}
 
-	// params
-	blocksPerSession := flag.Int64("blocksPerSession", -1, "override params.blocks_per_session.")
-	blockTimeInMin := flag.Int64("blockTimeInMin", -1, "override params.approx_block_time_in_min.")
-	flag.Parse()
+// Generates a single report and exits
+func runReport(args []string) {
+	fs := flag.NewFlagSet(os.Args[0], flag.ExitOnError)
+	now := time.Now().AddDate(0, 0, -1).Format("01-02-06T15:04:05")
+	resultFilePath := fs.String("results", "result/"+now+".json", "results file path")
+	format := fs.String("format", FormatJSON, "output format of the results. It can be: json (default), csv, sqlite, markdown or html")
+	partial := fs.Bool("partial", true, "write the report marked incomplete when some heights fail, instead of no report")
+	record := fs.String("record", "", "archive file the requests to the node and their responses are recorded to")
+	replay := fs.String("replay", "", "archive file recorded with -record the run is served from, without reaching the node")
+	previousFile := fs.String("previous", "", "json report the alert rules compare the report to, e.g. the report of the day before")
+	cf := newConfigFlags(fs)
+	lf := newLogFlags(fs).withProgress(fs)
+	_ = fs.Parse(args)
+	lf.apply()
 
-	log.Println("Attempting to read Config file:")
-	c := getConfig(*configFilePath)
+	if !isValidFormat(*format) {
+		fatal(NewInvalidFormatError(*format))
+	}
+	// the sqlite database accumulates the runs, so it does not default to a file per run
+	if *format == FormatSQLite && !isFlagPassed(fs, "results") {
+		*resultFilePath = DefaultSQLiteFile
+	}
 
-	// parse config file partial overrides
-	c = overrideConfig(
-		c,
-		*selector,
-		*timelineStart, *timelineEnd, *timelineUnit,
-		*startBlock, *endBlock,
-		*endpoint, *httpRetry,
-		*blocksPerSession, *blockTimeInMin,
-	)
+	c, err := cf.load()
+	if err != nil {
+		fatal(err)
+	}
+	setRunNotifier(c, CommandReport)
+	previous, err := readPreviousReport(*previousFile)
+	if err != nil {
+		fatal(err)
+	}
 
-	log.Println("Config Processed:")
-	log.Println(c)
+	pocket, err := runClient(&c, *record, *replay)
+	if err != nil {
+		fatal(err)
+	}
 
-	log.Println("Testing Pocket Endpoint")
-	if err := testEndpoint(c.Endpoint); err != nil {
-		log.Fatal(err)
+	opts, err := c.IndexerOptions()
+	if err != nil {
+		fatal(err)
 	}
 
-	blockReport := BlockReport{}
+	ctx := interruptContext()
+	logging.Info("Testing the pocket endpoint", "endpoint", pocket.Endpoint())
+	if err := pocket.Test(ctx); err != nil {
+		fatal(err)
+	}
 
-	if c.Selector == "timeline" {
-		log.Println("Converting Timeline To Block Heights")
-		report, err := ConvertTimelineToHeights(c)
-		if err != nil {
-			log.Fatal(err)
+	if c.Batch != nil {
+		runBatch(ctx, pocket, c, opts, *resultFilePath, *format, *partial, previous)
+		return
+	}
+	result, err := indexer.GenerateReport(ctx, pocket, opts)
+	saveRecording()
+	if err != nil && ctx.Err() != nil {
+		if _, ok := err.(*report.IncompleteError); !ok {
+			logging.Info("Interrupted before any heights were retrieved", "err", err.Error())
+			notifyFailure(err)
+			os.Exit(ExitCodeInterrupted)
 		}
-		blockReport.MinHeight = report.MinHeight
-		blockReport.MaxHeight = report.MaxHeight
-	} else if c.Selector == "byBlock" {
-		log.Println("Using byBlock as block selector.")
-		blockReport.MinHeight = c.ByBlock.Start
-		blockReport.MaxHeight = c.ByBlock.End
-	} else {
-		log.Fatal("selector must be one of following: timeline | byBlock")
+		logging.Info("Interrupted, writing the partial report", "min_height", result.BlockReport.MinHeight,
+			"max_height", result.BlockReport.MaxHeight, "file", *resultFilePath)
+		writeReport(result, c, *resultFilePath, *format)
+		notifyRun(notify.Incomplete(CommandReport, result, *resultFilePath, err))
+		os.Exit(ExitCodeInterrupted)
 	}
-
-	log.Println("Beginning to retrieve the transactions and claims from the blockchain")
-	blockTxsMap, claimsMap, startSupply, endSupply := GetChainData(blockReport.MinHeight, blockReport.MaxHeight, c)
-	log.Println("Creating a report from the blockchain data")
-	result := ProcessChainData(blockTxsMap, claimsMap, startSupply, endSupply, c.Selector, blockReport)
-	log.Println("Writing the result to a report file under " + *resultFilePath)
-	writeResultFile(result, *resultFilePath)
-	log.Println("Done")
+	if incompleteErr, ok := err.(*report.IncompleteError); ok && *partial {
+		logging.Error(incompleteErr.Error())
+		for _, failed := range incompleteErr.FailedHeights {
+			logging.Error("Failed height", "height", failed.Height, "phase", failed.Phase, "err", failed.Message)
+		}
+		logging.Info("Writing the incomplete report", "file", *resultFilePath)
+		writeReport(result, c, *resultFilePath, *format)
+		notifyRun(notify.Incomplete(CommandReport, result, *resultFilePath, err))
+		raiseAlerts(c, CommandReport, "", result, previous, *resultFilePath)
+		os.Exit(ExitCodeIncomplete)
+	}
+	if err != nil {
+		fatal(err)
+	}
+	logging.Info("Writing the report", "file", *resultFilePath)
+	writeReport(result, c, *resultFilePath, *format)
+	notifyRun(notify.Success(CommandReport, result, *resultFilePath))
+	raiseAlerts(c, CommandReport, "", result, previous, *resultFilePath)
+	logging.Info("Done")
 }
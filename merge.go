@@ -0,0 +1,47 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/pokt-network/relay_counter/logging"
+	"github.com/pokt-network/relay_counter/report"
+)
+
+const (
+	CommandMerge = "merge"
+)
+
+// Combines report files of adjacent ranges into a single report
+func runMerge(args []string) {
+	fs := flag.NewFlagSet(os.Args[0]+" "+CommandMerge, flag.ExitOnError)
+	now := time.Now().Format("01-02-06T15:04:05")
+	resultFilePath := fs.String("results", "result/"+CommandMerge+"_"+now+".json", "results file path")
+	format := fs.String("format", FormatJSON, "output format of the results. It can be: json (default), csv, sqlite, markdown or html")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s %s [flags] <report> <report> [<report>...]\n", os.Args[0], CommandMerge)
+		fs.PrintDefaults()
+	}
+	lf := newLogFlags(fs)
+	_ = fs.Parse(args)
+	lf.apply()
+	if fs.NArg() < 2 {
+		fs.Usage()
+		os.Exit(2)
+	}
+	if !isValidFormat(*format) {
+		fatal(NewInvalidFormatError(*format))
+	}
+
+	logging.Info("Merging the report files", "files", fs.NArg())
+	result, err := report.MergeFiles(fs.Args())
+	if err != nil {
+		fatal(err)
+	}
+	logging.Info("Merged the reports", "min_height", result.BlockReport.MinHeight, "max_height", result.BlockReport.MaxHeight)
+	logging.Info("Writing the report", "file", *resultFilePath)
+	writeReport(result, Config{}, *resultFilePath, *format)
+	logging.Info("Done")
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pokt-network/relay_counter/notify"
+)
+
+// The webhooks notified of the outcome of the runs
+type NotifyConfig struct {
+	Webhooks []notify.Webhook `json:"webhooks"`
+}
+
+func (n NotifyConfig) Validate() (errs []error) {
+	for i, w := range n.Webhooks {
+		name := w.Name
+		if name == "" {
+			name = fmt.Sprintf("#%d", i+1)
+		}
+		for _, err := range w.Validate() {
+			errs = append(errs, NewInvalidWebhookError(name, err))
+		}
+	}
+	return errs
+}
+
+// The notifier of the run, set once the config is loaded so the errors fatal exits on are notified too
+var (
+	runNotifier *notify.Notifier
+	runCommand  string
+)
+
+// Sets the notifier of the command, nil when the config has no webhooks
+func setRunNotifier(c Config, command string) {
+	if c.Notify == nil || len(c.Notify.Webhooks) == 0 {
+		return
+	}
+	runNotifier, runCommand = notify.New(c.Notify.Webhooks), command
+}
+
+// Sends the event of the run, once the run is over or interrupted, so each webhook is bounded by its own timeout
+func notifyRun(e notify.Event) {
+	runNotifier.Send(context.Background(), e)
+}
+
+func notifyFailure(err error) {
+	if runNotifier != nil {
+		notifyRun(notify.Failure(runCommand, err))
+	}
+}
@@ -0,0 +1,29 @@
+package notify
+
+import (
+	"fmt"
+)
+
+func NewInvalidURLError(u string) error {
+	return fmt.Errorf("ERROR: invalid webhook url %q, it must be an absolute url", u)
+}
+
+func NewInvalidEventError(event string) error {
+	return fmt.Errorf("ERROR: unrecognized webhook event: %s, valid events: (success, failure, alert)", event)
+}
+
+func NewInvalidTemplateError(err error) error {
+	return fmt.Errorf("ERROR: invalid webhook template: %s", err.Error())
+}
+
+func NewInvalidRetriesError(retries int) error {
+	return fmt.Errorf("ERROR: webhook retries must be 0 or greater, got %d", retries)
+}
+
+func NewInvalidTimeoutError(timeout string) error {
+	return fmt.Errorf("ERROR: invalid webhook timeout %q, it must be a positive duration such as 10s", timeout)
+}
+
+func NewWebhookStatusError(status int) error {
+	return fmt.Errorf("ERROR: the webhook responded with status %d", status)
+}
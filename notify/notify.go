@@ -0,0 +1,317 @@
+// Package notify posts the outcome of the runs to webhooks, as JSON or as a templated body for chat incoming-webhooks.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/pokt-network/relay_counter/alert"
+	"github.com/pokt-network/relay_counter/logging"
+	"github.com/pokt-network/relay_counter/report"
+)
+
+const (
+	EventSuccess = "success"
+	EventFailure = "failure"
+	EventAlert   = "alert"
+	// applied when the webhook does not set them
+	DefaultRetries = 3
+	DefaultTimeout = 10 * time.Second
+	// doubled after every failed attempt
+	retryDelay = time.Second
+)
+
+// A target of the notifications, every event is sent when On is empty
+type Webhook struct {
+	Name     string            `json:"name,omitempty"`
+	URL      string            `json:"url"`
+	On       []string          `json:"on,omitempty"`
+	Template string            `json:"template,omitempty"`
+	Headers  map[string]string `json:"headers,omitempty"`
+	Retries  *int              `json:"retries,omitempty"`
+	Timeout  string            `json:"timeout,omitempty"`
+}
+
+// The outcome of a run, the json payload of the webhooks without a template
+type Event struct {
+	Event   string `json:"event"`
+	Command string `json:"command"`
+	// the scheduled job, or the range of a batch
+	Job        string              `json:"job,omitempty"`
+	Profile    string              `json:"profile,omitempty"`
+	Endpoint   string              `json:"endpoint,omitempty"`
+	Range      *report.BlockReport `json:"range,omitempty"`
+	Output     string              `json:"output,omitempty"`
+	Totals     *Totals             `json:"totals,omitempty"`
+	Error      string              `json:"error,omitempty"`
+	Phase      string              `json:"phase,omitempty"`
+	LastHeight int64               `json:"last_height,omitempty"`
+	Alerts     []alert.Alert       `json:"alerts,omitempty"`
+	Time       time.Time           `json:"time"`
+}
+
+type Totals struct {
+	Relays        int64 `json:"relays"`
+	Challenges    int64 `json:"challenges"`
+	Minted        int64 `json:"minted"`
+	GoodTxs       int64 `json:"good_txs"`
+	BadTxs        int64 `json:"bad_txs"`
+	ProofTxs      int64 `json:"proof_txs"`
+	Incomplete    bool  `json:"incomplete,omitempty"`
+	FailedHeights int   `json:"failed_heights,omitempty"`
+}
+
+// The event of a report written to output
+func Success(command string, result report.Report, output string) Event {
+	e := Event{
+		Event:   EventSuccess,
+		Command: command,
+		Range:   &result.BlockReport,
+		Output:  output,
+		Totals: &Totals{
+			Relays:        result.TotalRelaysCompleted,
+			Challenges:    result.TotalChallengesCompleted,
+			Minted:        result.TotalMinted,
+			GoodTxs:       result.TotalGoodTxs,
+			BadTxs:        result.TotalBadTxs,
+			ProofTxs:      result.TotalProofTxs,
+			Incomplete:    result.Incomplete,
+			FailedHeights: len(result.FailedHeights),
+		},
+		Time: time.Now().UTC(),
+	}
+	if result.Metadata != nil {
+		e.Profile, e.Endpoint = result.Metadata.Profile, result.Metadata.Endpoint
+	}
+	return e
+}
+
+// The event of a failed run, the phase and height are those of the failed height when err holds one
+func Failure(command string, err error) Event {
+	e := Event{Event: EventFailure, Command: command, Error: err.Error(), Time: time.Now().UTC()}
+	var heightErr report.HeightError
+	var incompleteErr *report.IncompleteError
+	switch {
+	case errors.As(err, &heightErr):
+		e.Phase, e.LastHeight = heightErr.Phase, heightErr.Height
+	case errors.As(err, &incompleteErr) && len(incompleteErr.FailedHeights) != 0:
+		last := incompleteErr.FailedHeights[len(incompleteErr.FailedHeights)-1]
+		e.Phase, e.LastHeight = last.Phase, last.Height
+	}
+	return e
+}
+
+// The event of a report written with some heights missing, a failure that still has the totals and output of the report
+func Incomplete(command string, result report.Report, output string, err error) Event {
+	e, failure := Success(command, result, output), Failure(command, err)
+	e.Event, e.Error, e.Phase, e.LastHeight = failure.Event, failure.Error, failure.Phase, failure.LastHeight
+	return e
+}
+
+// The event of the alerts raised by a report written to output
+func Alerts(command string, result report.Report, output string, alerts []alert.Alert) Event {
+	e := Success(command, result, output)
+	e.Event, e.Alerts = EventAlert, alerts
+	return e
+}
+
+// A line describing the event, for the templates of chat webhooks
+func (e Event) Summary() string {
+	name := "relay_counter " + e.Command
+	if e.Job != "" {
+		name += " " + e.Job
+	}
+	if e.Event == EventAlert {
+		messages := make([]string, 0, len(e.Alerts))
+		for _, a := range e.Alerts {
+			messages = append(messages, "["+a.Severity+"] "+a.Message)
+		}
+		return fmt.Sprintf("%s raised %d alerts on heights %d to %d: %s", name, len(e.Alerts), e.Range.MinHeight, e.Range.MaxHeight, strings.Join(messages, "; "))
+	}
+	if e.Event == EventFailure {
+		s := name + " failed: " + e.Error
+		if e.Range != nil {
+			s += fmt.Sprintf(" (heights %d to %d)", e.Range.MinHeight, e.Range.MaxHeight)
+		}
+		return s
+	}
+	s := fmt.Sprintf("%s succeeded: heights %d to %d", name, e.Range.MinHeight, e.Range.MaxHeight)
+	if e.Totals != nil {
+		s += fmt.Sprintf(", %d relays, %d good txs, %d bad txs", e.Totals.Relays, e.Totals.GoodTxs, e.Totals.BadTxs)
+	}
+	if e.Output != "" {
+		s += ", written to " + e.Output
+	}
+	return s
+}
+
+// Checks the webhook without sending anything
+func (w Webhook) Validate() (errs []error) {
+	if u, err := url.Parse(w.URL); err != nil || u.Scheme == "" || u.Host == "" {
+		errs = append(errs, NewInvalidURLError(w.URL))
+	}
+	for _, on := range w.On {
+		if on != EventSuccess && on != EventFailure && on != EventAlert {
+			errs = append(errs, NewInvalidEventError(on))
+		}
+	}
+	if _, err := w.template(); err != nil {
+		errs = append(errs, NewInvalidTemplateError(err))
+	}
+	if w.Retries != nil && *w.Retries < 0 {
+		errs = append(errs, NewInvalidRetriesError(*w.Retries))
+	}
+	if _, err := w.timeout(); err != nil {
+		errs = append(errs, NewInvalidTimeoutError(w.Timeout))
+	}
+	return errs
+}
+
+// The name of the webhook in the logs, its host when it has none, the url may hold a secret
+func (w Webhook) String() string {
+	if w.Name != "" {
+		return w.Name
+	}
+	if u, err := url.Parse(w.URL); err == nil {
+		return u.Host
+	}
+	return ""
+}
+
+func (w Webhook) subscribed(event string) bool {
+	if len(w.On) == 0 {
+		return true
+	}
+	for _, on := range w.On {
+		if on == event {
+			return true
+		}
+	}
+	return false
+}
+
+func (w Webhook) template() (*template.Template, error) {
+	if w.Template == "" {
+		return nil, nil
+	}
+	return template.New(w.String()).Funcs(template.FuncMap{
+		// quotes and escapes a value, e.g. {"text": {{json .Summary}}}
+		"json": func(v interface{}) (string, error) {
+			bz, err := json.Marshal(v)
+			return string(bz), err
+		},
+	}).Parse(w.Template)
+}
+
+func (w Webhook) timeout() (time.Duration, error) {
+	if w.Timeout == "" {
+		return DefaultTimeout, nil
+	}
+	timeout, err := time.ParseDuration(w.Timeout)
+	if err == nil && timeout <= 0 {
+		err = NewInvalidTimeoutError(w.Timeout)
+	}
+	return timeout, err
+}
+
+func (w Webhook) body(e Event) ([]byte, error) {
+	tmpl, err := w.template()
+	if err != nil || tmpl == nil {
+		return json.Marshal(e)
+	}
+	var buf bytes.Buffer
+	err = tmpl.Execute(&buf, e)
+	return buf.Bytes(), err
+}
+
+// Sends the events to the webhooks subscribed to them
+type Notifier struct {
+	webhooks []Webhook
+	client   *http.Client
+}
+
+func New(webhooks []Webhook) *Notifier {
+	return &Notifier{webhooks: webhooks, client: &http.Client{}}
+}
+
+// Sends the event to every webhook subscribed, retrying the failed requests. The failures are logged, a notification
+// never fails the run
+func (n *Notifier) Send(ctx context.Context, e Event) {
+	if n == nil {
+		return
+	}
+	for _, w := range n.webhooks {
+		if !w.subscribed(e.Event) {
+			continue
+		}
+		if err := n.send(ctx, w, e); err != nil {
+			logging.Error("Could not notify the webhook", "webhook", w.String(), "event", e.Event, "err", err.Error())
+			continue
+		}
+		logging.Info("Notified the webhook", "webhook", w.String(), "event", e.Event)
+	}
+}
+
+func (n *Notifier) send(ctx context.Context, w Webhook, e Event) error {
+	body, err := w.body(e)
+	if err != nil {
+		return NewInvalidTemplateError(err)
+	}
+	retries := DefaultRetries
+	if w.Retries != nil {
+		retries = *w.Retries
+	}
+	timeout, _ := w.timeout()
+	delay := retryDelay
+	for attempt := 0; ; attempt++ {
+		retry, err := n.post(ctx, w, body, timeout)
+		if err == nil || !retry || attempt >= retries {
+			return err
+		}
+		logging.Info("Retrying the webhook", "webhook", w.String(), "attempt", attempt+1, "err", err.Error())
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+		delay *= 2
+	}
+}
+
+// Posts the body, retry is false when the webhook rejected it and sending it again would not help
+func (n *Notifier) post(ctx context.Context, w Webhook, body []byte, timeout time.Duration) (retry bool, err error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	req, err := http.NewRequest(http.MethodPost, w.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range w.Headers {
+		req.Header.Set(key, value)
+	}
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(ioutil.Discard, resp.Body)
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+	retry = resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+	return retry, NewWebhookStatusError(resp.StatusCode)
+}
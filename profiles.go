@@ -0,0 +1,69 @@
+package main
+
+import (
+	"sort"
+)
+
+const (
+	ProfileMainnet  = "mainnet"
+	ProfileTestnet  = "testnet"
+	ProfileLocalnet = "localnet"
+)
+
+// Keys set by the built-in profiles, a profile of the same name in the config file is applied over them
+var builtinProfiles = map[string]map[string]interface{}{
+	ProfileMainnet: {
+		"endpoint": "https://dispatch-1.nodes.pokt.network:4201/v1",
+		"params":   map[string]interface{}{"blocks_per_session": 4, "approx_block_time_in_min": 15},
+	},
+	ProfileTestnet: {
+		"endpoint": "https://node1.testnet.pokt.network:443/v1",
+		"params":   map[string]interface{}{"blocks_per_session": 4, "approx_block_time_in_min": 15},
+	},
+	ProfileLocalnet: {
+		"endpoint":   "http://localhost:8081/v1",
+		"http_retry": 0,
+		"params":     map[string]interface{}{"blocks_per_session": 4, "approx_block_time_in_min": 1},
+	},
+}
+
+// The name of the profile the layers select, the last layer setting it wins
+func selectedProfile(layers ...map[string]interface{}) (profile string) {
+	for _, layer := range layers {
+		if p, ok := layer["profile"].(string); ok && p != "" {
+			profile = p
+		}
+	}
+	return
+}
+
+// The keys of the built-in profile and of the profile of the config file, nil when there is none, the profile must be one of them
+func profileLayers(profile string, fileProfiles map[string]interface{}) (builtin, file map[string]interface{}, err error) {
+	builtin = builtinProfiles[profile]
+	if value, ok := fileProfiles[profile]; ok {
+		if file, ok = value.(map[string]interface{}); !ok {
+			return nil, nil, NewInvalidProfileError(profile)
+		}
+	}
+	if builtin == nil && file == nil {
+		return nil, nil, NewUnknownProfileError(profile, profileNames(fileProfiles))
+	}
+	return builtin, file, nil
+}
+
+// The built-in profiles and those of the config file, sorted
+func profileNames(fileProfiles map[string]interface{}) []string {
+	seen := make(map[string]bool)
+	for name := range builtinProfiles {
+		seen[name] = true
+	}
+	for name := range fileProfiles {
+		seen[name] = true
+	}
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
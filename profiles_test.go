@@ -0,0 +1,148 @@
+package main
+
+import (
+	"flag"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+// Loads the config like a command would, from the file written in a temp dir, the environment variables and the args
+func loadTestConfig(t *testing.T, name, content string, env map[string]string, args ...string) (Config, error) {
+	t.Helper()
+	file := filepath.Join(t.TempDir(), name)
+	if err := ioutil.WriteFile(file, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for k, v := range env {
+		t.Setenv(k, v)
+	}
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	cf := newConfigFlags(fs)
+	if err := fs.Parse(append([]string{"-config=" + file}, args...)); err != nil {
+		t.Fatal(err)
+	}
+	return cf.load()
+}
+
+// Every layer overrides the keys of the previous ones: defaults < file < built-in profile < file profile < env < flags
+func TestProfilePrecedence(t *testing.T) {
+	tests := []struct {
+		name      string
+		file      string
+		env       map[string]string
+		args      []string
+		endpoint  string
+		httpRetry int
+		blockTime int64
+		start     int64
+	}{
+		{
+			name:      "defaults",
+			file:      `{}`,
+			endpoint:  "http://localhost:8081/v1",
+			httpRetry: 3,
+			blockTime: 15,
+			start:     -1,
+		},
+		{
+			name:      "top level keys of the file over the defaults",
+			file:      `{"endpoint": "http://file:8081/v1", "http_retry": 5, "timeline": {"start": -7}}`,
+			endpoint:  "http://file:8081/v1",
+			httpRetry: 5,
+			blockTime: 15,
+			start:     -7,
+		},
+		{
+			name:      "built-in profile over the file",
+			file:      `{"endpoint": "http://file:8081/v1", "http_retry": 5, "timeline": {"start": -7}}`,
+			args:      []string{"-profile=localnet"},
+			endpoint:  "http://localhost:8081/v1",
+			httpRetry: 0,
+			blockTime: 1,
+			start:     -7,
+		},
+		{
+			name: "profile of the file over the built-in one",
+			file: `{"endpoint": "http://file:8081/v1", "http_retry": 5, "timeline": {"start": -7},
+				"profiles": {"localnet": {"http_retry": 2}}}`,
+			args:      []string{"-profile=localnet"},
+			endpoint:  "http://localhost:8081/v1",
+			httpRetry: 2,
+			blockTime: 1,
+			start:     -7,
+		},
+		{
+			name: "environment over the profile of the file",
+			file: `{"endpoint": "http://file:8081/v1", "http_retry": 5, "timeline": {"start": -7},
+				"profiles": {"localnet": {"http_retry": 2}}}`,
+			env:       map[string]string{"RELAY_COUNTER_HTTP_RETRY": "6", "RELAY_COUNTER_TIMELINE_START": "-6"},
+			args:      []string{"-profile=localnet"},
+			endpoint:  "http://localhost:8081/v1",
+			httpRetry: 6,
+			blockTime: 1,
+			start:     -6,
+		},
+		{
+			name: "flags over the environment",
+			file: `{"endpoint": "http://file:8081/v1", "http_retry": 5, "timeline": {"start": -7},
+				"profiles": {"localnet": {"http_retry": 2}}}`,
+			env:       map[string]string{"RELAY_COUNTER_HTTP_RETRY": "6", "RELAY_COUNTER_TIMELINE_START": "-6"},
+			args:      []string{"-profile=localnet", "-httpRetry=7"},
+			endpoint:  "http://localhost:8081/v1",
+			httpRetry: 7,
+			blockTime: 1,
+			start:     -6,
+		},
+		{
+			name:      "profile selected by the file",
+			file:      `{"profile": "mainnet"}`,
+			endpoint:  builtinProfiles[ProfileMainnet]["endpoint"].(string),
+			httpRetry: 3,
+			blockTime: 15,
+			start:     -1,
+		},
+		{
+			name:      "profile of the file without a built-in one",
+			file:      `{"profiles": {"staging": {"endpoint": "http://staging:8081/v1"}}}`,
+			args:      []string{"-profile=staging"},
+			endpoint:  "http://staging:8081/v1",
+			httpRetry: 3,
+			blockTime: 15,
+			start:     -1,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := loadTestConfig(t, "config.json", tt.file, tt.env, tt.args...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if c.Endpoint != tt.endpoint {
+				t.Errorf("expected the endpoint %s, got %s", tt.endpoint, c.Endpoint)
+			}
+			if c.HTTPRetry != tt.httpRetry {
+				t.Errorf("expected %d retries, got %d", tt.httpRetry, c.HTTPRetry)
+			}
+			if c.Params.AppxBlockTimeInMinutes != tt.blockTime {
+				t.Errorf("expected a block time of %d minutes, got %d", tt.blockTime, c.Params.AppxBlockTimeInMinutes)
+			}
+			if c.Timeline.Start != tt.start {
+				t.Errorf("expected the timeline to start at %d, got %d", tt.start, c.Timeline.Start)
+			}
+		})
+	}
+}
+
+func TestUnknownProfile(t *testing.T) {
+	_, err := loadTestConfig(t, "config.json", `{"profiles": {"staging": {}}}`, nil, "-profile=unknown")
+	configErr, ok := err.(*ConfigError)
+	if !ok {
+		t.Fatalf("expected a config error, got %v", err)
+	}
+	expected := NewUnknownProfileError("unknown", []string{"localnet", "mainnet", "staging", "testnet"})
+	if len(configErr.Errors) != 1 || configErr.Errors[0].Error() != expected.Error() {
+		t.Fatalf("expected %v, got %v", expected, configErr.Errors)
+	}
+}
@@ -0,0 +1,232 @@
+// Package progress reports how far along the phases of a run are, as a live line on a terminal or as periodic log lines.
+package progress
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pokt-network/relay_counter/logging"
+)
+
+const (
+	PhaseHeights    = "heights" // resolving the heights of a timeline
+	PhaseBlockTxs   = "blocktxs"
+	PhaseClaims     = "claims"
+	PhaseProcessing = "processing"
+	// time between redraws of the live line
+	liveThrottle = 100 * time.Millisecond
+)
+
+// nil until SetReporter is called, the phases are not tracked
+var reporter *Reporter
+
+// Tracks the phases of the run, meant to be called once at startup
+func SetReporter(r *Reporter) {
+	reporter = r
+}
+
+// Starts tracking a phase of total steps, nil (which ignores the updates) when no reporter is set
+func Start(name string, total int64) *Phase {
+	if reporter == nil {
+		return nil
+	}
+	return reporter.Start(name, total)
+}
+
+// Renders the phases either as a line redrawn in place on a terminal (live), or as log lines every interval
+type Reporter struct {
+	w        io.Writer
+	live     bool
+	interval time.Duration
+	mu       sync.Mutex
+	phases   []*Phase
+	line     string
+	lastDraw time.Time
+}
+
+func NewReporter(w io.Writer, live bool, interval time.Duration) *Reporter {
+	return &Reporter{w: w, live: live, interval: interval}
+}
+
+func (r *Reporter) Start(name string, total int64) *Phase {
+	p := &Phase{reporter: r, name: name, total: total, start: time.Now()}
+	r.mu.Lock()
+	r.phases = append(r.phases, p)
+	r.mu.Unlock()
+	return p
+}
+
+// A writer for the logs of a live reporter: the live line is cleared before each log line and redrawn after it
+func (r *Reporter) Writer() io.Writer {
+	return liveWriter{r}
+}
+
+type liveWriter struct {
+	r *Reporter
+}
+
+func (lw liveWriter) Write(p []byte) (int, error) {
+	lw.r.mu.Lock()
+	defer lw.r.mu.Unlock()
+	if lw.r.line != "" {
+		_, _ = io.WriteString(lw.r.w, "\r\033[K")
+	}
+	n, err := lw.r.w.Write(p)
+	if lw.r.line != "" {
+		_, _ = io.WriteString(lw.r.w, lw.r.line)
+	}
+	return n, err
+}
+
+// A phase of the run, its methods do nothing on a nil phase
+type Phase struct {
+	reporter *Reporter
+	name     string
+	total    int64
+	start    time.Time
+	// guarded by the mutex of the reporter
+	done    int64
+	retries int64
+	logged  time.Time
+	ended   bool
+}
+
+// Adds n completed steps
+func (p *Phase) Add(n int64) {
+	if p == nil {
+		return
+	}
+	p.reporter.update(p, func() {
+		p.done += n
+		// the total of some phases is an estimate
+		if p.done > p.total {
+			p.total = p.done
+		}
+	})
+}
+
+// Wraps fn, counting every call after the first as a retry of the phase
+func (p *Phase) Attempts(fn func() error) func() error {
+	if p == nil {
+		return fn
+	}
+	calls := 0
+	return func() error {
+		if calls > 0 {
+			p.reporter.update(p, func() { p.retries++ })
+		}
+		calls++
+		return fn()
+	}
+}
+
+// Ends the phase, whether it completed or not
+func (p *Phase) Done() {
+	if p == nil {
+		return
+	}
+	p.reporter.update(p, func() { p.ended = true })
+}
+
+// Applies the change to the phase and renders the phases when due
+func (r *Reporter) update(p *Phase, change func()) {
+	r.mu.Lock()
+	change()
+	now := time.Now()
+	if r.live {
+		r.drawLive(now)
+		r.mu.Unlock()
+		return
+	}
+	var entries [][]interface{}
+	if p.ended {
+		entries = append(entries, append([]interface{}{"Phase done"}, p.fields(now)...))
+		r.remove(p)
+	} else if now.Sub(p.logged) >= r.interval {
+		p.logged = now
+		entries = append(entries, append([]interface{}{"Progress"}, p.fields(now)...))
+	}
+	r.mu.Unlock()
+	// logged outside the lock, the log writer may be the live writer of this reporter
+	for _, e := range entries {
+		logging.Info(e[0].(string), e[1:]...)
+	}
+}
+
+// Redraws the live line, ending it with a new line once every phase it shows is done
+func (r *Reporter) drawLive(now time.Time) {
+	allEnded := true
+	for _, p := range r.phases {
+		allEnded = allEnded && p.ended
+	}
+	if !allEnded && now.Sub(r.lastDraw) < liveThrottle {
+		return
+	}
+	r.lastDraw = now
+	parts := make([]string, 0, len(r.phases))
+	for _, p := range r.phases {
+		parts = append(parts, p.summary(now))
+	}
+	line := strings.Join(parts, " | ")
+	_, _ = io.WriteString(r.w, "\r\033[K"+line)
+	r.line = line
+	if allEnded {
+		_, _ = io.WriteString(r.w, "\n")
+		r.line = ""
+		r.phases = nil
+	}
+}
+
+func (r *Reporter) remove(p *Phase) {
+	for i, other := range r.phases {
+		if other == p {
+			r.phases = append(r.phases[:i], r.phases[i+1:]...)
+			return
+		}
+	}
+}
+
+// Steps per second since the phase started
+func (p *Phase) rate(now time.Time) float64 {
+	elapsed := now.Sub(p.start).Seconds()
+	if elapsed <= 0 {
+		return 0
+	}
+	return float64(p.done) / elapsed
+}
+
+// Estimated time left at the current rate, 0 when unknown
+func (p *Phase) eta(now time.Time) time.Duration {
+	rate := p.rate(now)
+	if rate == 0 || p.done >= p.total {
+		return 0
+	}
+	return time.Duration(float64(p.total-p.done) / rate * float64(time.Second)).Round(time.Second)
+}
+
+func (p *Phase) percent() float64 {
+	if p.total <= 0 {
+		return 100
+	}
+	return float64(p.done) * 100 / float64(p.total)
+}
+
+func (p *Phase) fields(now time.Time) []interface{} {
+	fields := []interface{}{"phase", p.name, "done", p.done, "total", p.total, "percent", fmt.Sprintf("%.1f", p.percent()),
+		"rate", fmt.Sprintf("%.2f/s", p.rate(now)), "retries", p.retries}
+	if p.ended {
+		return append(fields, "duration", now.Sub(p.start).Round(time.Millisecond).String())
+	}
+	return append(fields, "eta", p.eta(now).String())
+}
+
+func (p *Phase) summary(now time.Time) string {
+	s := fmt.Sprintf("%s %5.1f%% %d/%d %.2f/s retries %d", p.name, p.percent(), p.done, p.total, p.rate(now), p.retries)
+	if p.ended {
+		return s + " done in " + now.Sub(p.start).Round(time.Second).String()
+	}
+	return s + " ETA " + p.eta(now).String()
+}
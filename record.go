@@ -0,0 +1,52 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/pokt-network/relay_counter/client"
+	"github.com/pokt-network/relay_counter/logging"
+)
+
+// The recorder of the run and the archive file it is saved to, set by runClient so fatal saves it too
+var (
+	runRecorder   *client.Recorder
+	runRecordFile string
+)
+
+// The client of a report run: recording its requests when recordFile is set, or answering them from the
+// archive of replayFile without reaching any node. A replay uses the endpoint of the archive, so its metadata matches the recording
+func runClient(c *Config, recordFile, replayFile string) (*client.Client, error) {
+	if recordFile != "" && replayFile != "" {
+		return nil, NewRecordAndReplayError()
+	}
+	if replayFile != "" {
+		archive, err := client.LoadArchive(replayFile)
+		if err != nil {
+			return nil, err
+		}
+		logging.Info("Replaying the archive", "requests", len(archive.Interactions), "endpoint", archive.Endpoint, "recorded_at", archive.RecordedAt.String())
+		c.Endpoint = archive.Endpoint
+		return c.Client(client.WithHTTPClient(&http.Client{Transport: client.NewReplayer(archive)})), nil
+	}
+	if recordFile != "" {
+		runRecorder, runRecordFile = client.NewRecorder(c.Endpoint, nil), recordFile
+		return c.Client(client.WithHTTPClient(&http.Client{Transport: runRecorder})), nil
+	}
+	return c.Client(), nil
+}
+
+// Saves the requests of the run once, whatever its outcome, so a failed run can be reproduced. Called before the report
+// is written and by fatal, for the runs failing before any report
+func saveRecording() {
+	recorder, file := runRecorder, runRecordFile
+	if recorder == nil {
+		return
+	}
+	runRecorder = nil
+	archive := recorder.Archive()
+	if err := archive.Save(file); err != nil {
+		logging.Error("Unable to save the recorded requests", "err", err.Error())
+		return
+	}
+	logging.Info("Recorded the requests", "requests", len(archive.Interactions), "file", file)
+}
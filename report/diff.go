@@ -0,0 +1,163 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+)
+
+// The change of a counter between two reports
+type Delta struct {
+	Old     int64    `json:"old"`
+	New     int64    `json:"new"`
+	Change  int64    `json:"change"`
+	Percent *float64 `json:"percent,omitempty"` // unset when the old value is 0
+}
+
+type Diff struct {
+	OldFile          string           `json:"old_file"`
+	NewFile          string           `json:"new_file"`
+	OldBlockReport   BlockReport      `json:"old_block_report"`
+	NewBlockReport   BlockReport      `json:"new_block_report"`
+	Totals           map[string]Delta `json:"totals"`
+	Chains           map[string]Delta `json:"chains"`
+	Nodes            map[string]Delta `json:"nodes"`
+	Apps             map[string]Delta `json:"apps"`
+	NewNodes         []string         `json:"new_nodes"`
+	DisappearedNodes []string         `json:"disappeared_nodes"`
+	NewApps          []string         `json:"new_apps"`
+	DisappearedApps  []string         `json:"disappeared_apps"`
+}
+
+func NewDelta(oldValue, newValue int64) Delta {
+	d := Delta{Old: oldValue, New: newValue, Change: newValue - oldValue}
+	if oldValue != 0 {
+		p := float64(d.Change) / float64(oldValue) * 100
+		d.Percent = &p
+	}
+	return d
+}
+
+// Compares the totals, chains, nodes and apps of two reports
+func Compare(oldReport, newReport Report) Diff {
+	d := Diff{
+		OldBlockReport: oldReport.BlockReport,
+		NewBlockReport: newReport.BlockReport,
+		Totals: map[string]Delta{
+			"total_relays_completed":     NewDelta(oldReport.TotalRelaysCompleted, newReport.TotalRelaysCompleted),
+			"total_challenges_completed": NewDelta(oldReport.TotalChallengesCompleted, newReport.TotalChallengesCompleted),
+			"total_minted":               NewDelta(oldReport.TotalMinted, newReport.TotalMinted),
+			"total_good_txs":             NewDelta(oldReport.TotalGoodTxs, newReport.TotalGoodTxs),
+			"total_bad_txs":              NewDelta(oldReport.TotalBadTxs, newReport.TotalBadTxs),
+			"proof_msgs":                 NewDelta(oldReport.TotalProofTxs, newReport.TotalProofTxs),
+		},
+		NewNodes:         make([]string, 0),
+		DisappearedNodes: make([]string, 0),
+		NewApps:          make([]string, 0),
+		DisappearedApps:  make([]string, 0),
+	}
+	d.Chains = diffCounts(RelaysByChain(oldReport), RelaysByChain(newReport))
+	oldNodes, newNodes := make(map[string]int64), make(map[string]int64)
+	for address, nodeReport := range oldReport.NodeReports {
+		oldNodes[address] = nodeReport.TotalRelays
+	}
+	for address, nodeReport := range newReport.NodeReports {
+		newNodes[address] = nodeReport.TotalRelays
+	}
+	d.Nodes = diffCounts(oldNodes, newNodes)
+	d.NewNodes, d.DisappearedNodes = diffKeys(oldNodes, newNodes)
+	oldApps, newApps := make(map[string]int64), make(map[string]int64)
+	for address, appReport := range oldReport.AppReports {
+		oldApps[address] = appReport.TotalRelays
+	}
+	for address, appReport := range newReport.AppReports {
+		newApps[address] = appReport.TotalRelays
+	}
+	d.Apps = diffCounts(oldApps, newApps)
+	d.NewApps, d.DisappearedApps = diffKeys(oldApps, newApps)
+	return d
+}
+
+func diffCounts(oldCounts, newCounts map[string]int64) map[string]Delta {
+	deltas := make(map[string]Delta)
+	for key, oldValue := range oldCounts {
+		deltas[key] = NewDelta(oldValue, newCounts[key])
+	}
+	for key, newValue := range newCounts {
+		if _, ok := oldCounts[key]; !ok {
+			deltas[key] = NewDelta(0, newValue)
+		}
+	}
+	return deltas
+}
+
+// Returns the keys only found in the new counts and the keys only found in the old counts
+func diffKeys(oldCounts, newCounts map[string]int64) (added, removed []string) {
+	added, removed = make([]string, 0), make([]string, 0)
+	for key := range newCounts {
+		if _, ok := oldCounts[key]; !ok {
+			added = append(added, key)
+		}
+	}
+	for key := range oldCounts {
+		if _, ok := newCounts[key]; !ok {
+			removed = append(removed, key)
+		}
+	}
+	sort.Strings(added)
+	sort.Strings(removed)
+	return
+}
+
+// Writes the diff as tables, the rows of every section sorted by the largest absolute change
+func (d Diff) WriteTable(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(tw, "Old:\t%s\t(heights %d through %d)\n", d.OldFile, d.OldBlockReport.MinHeight, d.OldBlockReport.MaxHeight)
+	fmt.Fprintf(tw, "New:\t%s\t(heights %d through %d)\n", d.NewFile, d.NewBlockReport.MinHeight, d.NewBlockReport.MaxHeight)
+	for _, section := range []struct {
+		title  string
+		deltas map[string]Delta
+	}{
+		{"TOTALS", d.Totals},
+		{"CHAINS", d.Chains},
+		{"NODES", d.Nodes},
+		{"APPS", d.Apps},
+	} {
+		fmt.Fprintf(tw, "\n%s\tOLD\tNEW\tCHANGE\tPERCENT\n", section.title)
+		for _, key := range sortedByChange(section.deltas) {
+			delta := section.deltas[key]
+			percent := "n/a"
+			if delta.Percent != nil {
+				percent = fmt.Sprintf("%+.2f%%", *delta.Percent)
+			}
+			fmt.Fprintf(tw, "%s\t%d\t%d\t%+d\t%s\n", key, delta.Old, delta.New, delta.Change, percent)
+		}
+	}
+	fmt.Fprintf(tw, "\nNew nodes:\t%d\t%v\n", len(d.NewNodes), d.NewNodes)
+	fmt.Fprintf(tw, "Disappeared nodes:\t%d\t%v\n", len(d.DisappearedNodes), d.DisappearedNodes)
+	fmt.Fprintf(tw, "New apps:\t%d\t%v\n", len(d.NewApps), d.NewApps)
+	fmt.Fprintf(tw, "Disappeared apps:\t%d\t%v\n", len(d.DisappearedApps), d.DisappearedApps)
+	return tw.Flush()
+}
+
+func sortedByChange(deltas map[string]Delta) []string {
+	keys := make([]string, 0, len(deltas))
+	for key := range deltas {
+		keys = append(keys, key)
+	}
+	abs := func(v int64) int64 {
+		if v < 0 {
+			return -v
+		}
+		return v
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		ci, cj := abs(deltas[keys[i]].Change), abs(deltas[keys[j]].Change)
+		if ci != cj {
+			return ci > cj
+		}
+		return keys[i] < keys[j]
+	})
+	return keys
+}
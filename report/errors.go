@@ -0,0 +1,76 @@
+package report
+
+import (
+	"fmt"
+)
+
+const (
+	PhaseBlock    = "block"
+	PhaseBlockTxs = "blocktxs"
+	PhaseClaims   = "claims"
+	PhaseProcess  = "process"
+	// the supply at the height, used for the minted total
+	PhaseSupply = "supply"
+	// the run was interrupted at the height, the range is cut short before it
+	PhaseCancelled = "cancelled"
+)
+
+func NewPublicKeyError(pkHex string) error {
+	return fmt.Errorf("ERROR: unable to convert string public key %q into ED25519 public key", pkHex)
+}
+
+func NewFilterFileError(file string, err error) error {
+	return fmt.Errorf("ERROR: unable to read filter file %s: %s", file, err.Error())
+}
+
+func NewInvalidGroupError(name string, err error) error {
+	return fmt.Errorf("ERROR: invalid group %s: %s", name, err.Error())
+}
+
+func NewInvalidReportFileError(file string, err error) error {
+	return fmt.Errorf("ERROR: unable to parse report file %s: %s", file, err.Error())
+}
+
+func NewOverlappingReportsError(file1 string, r1 BlockReport, file2 string, r2 BlockReport) error {
+	return fmt.Errorf("ERROR: reports overlap: %s (heights %d through %d) and %s (heights %d through %d)", file1, r1.MinHeight, r1.MaxHeight, file2, r2.MinHeight, r2.MaxHeight)
+}
+
+func NewNonContiguousReportsError(file1 string, r1 BlockReport, file2 string, r2 BlockReport) error {
+	return fmt.Errorf("ERROR: reports are not contiguous, heights %d through %d are missing between %s and %s", r1.MaxHeight, r2.MinHeight, file1, file2)
+}
+
+func NewMismatchedFiltersError(file1, file2 string) error {
+	return fmt.Errorf("ERROR: reports %s and %s were generated with different filters", file1, file2)
+}
+
+func NewUndocumentedFieldError(typeName, field string) error {
+	return fmt.Errorf("ERROR: the report field %s.%s has no description in the schema", typeName, field)
+}
+
+func NewUnsupportedSchemaTypeError(typeName string) error {
+	return fmt.Errorf("ERROR: unable to generate the schema of type %s", typeName)
+}
+
+// A height that could not be retrieved or processed, listed in the report when it is incomplete
+type HeightError struct {
+	Height  int64  `json:"height"`
+	Phase   string `json:"phase"`
+	Message string `json:"error"`
+}
+
+func NewHeightError(height int64, phase string, err error) HeightError {
+	return HeightError{Height: height, Phase: phase, Message: err.Error()}
+}
+
+func (e HeightError) Error() string {
+	return fmt.Sprintf("height %d (%s): %s", e.Height, e.Phase, e.Message)
+}
+
+// Returned with the report when some heights failed, the report holds the data of the other heights
+type IncompleteError struct {
+	FailedHeights []HeightError
+}
+
+func (e *IncompleteError) Error() string {
+	return fmt.Sprintf("ERROR: the report is incomplete, %d heights failed, first failure: %s", len(e.FailedHeights), e.FailedHeights[0].Error())
+}
@@ -0,0 +1,167 @@
+package report
+
+import (
+	"bufio"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/pokt-network/pocket-core/crypto"
+)
+
+const (
+	PublicKeyHexLength = 64
+)
+
+type Filters struct {
+	Nodes      []string `json:"nodes"`
+	NodesFile  string   `json:"nodes_file"`
+	Apps       []string `json:"apps"`
+	AppsFile   string   `json:"apps_file"`
+	Chains     []string `json:"chains"`
+	ChainsFile string   `json:"chains_file"`
+}
+
+// A set of node addresses, app addresses and relay chains the report is scoped to, an empty set matches everything
+type Filter struct {
+	Nodes  map[string]struct{}
+	Apps   map[string]struct{}
+	Chains map[string]struct{}
+}
+
+type FilteredReport struct {
+	Nodes                    []string         `json:"nodes,omitempty"`
+	Apps                     []string         `json:"apps,omitempty"`
+	Chains                   []string         `json:"chains,omitempty"`
+	TotalRelaysCompleted     int64            `json:"total_relays_completed"`
+	TotalChallengesCompleted int64            `json:"total_challenges_completed"`
+	TotalProofTxs            int64            `json:"proof_msgs"`
+	RelaysByChain            map[string]int64 `json:"relays_by_chain"`
+}
+
+// Builds the filter from the inline lists and the list files in the config
+func NewFilter(f Filters) (filter Filter, err error) {
+	nodes, err := loadFilterList(f.Nodes, f.NodesFile)
+	if err != nil {
+		return filter, err
+	}
+	apps, err := loadFilterList(f.Apps, f.AppsFile)
+	if err != nil {
+		return filter, err
+	}
+	chains, err := loadFilterList(f.Chains, f.ChainsFile)
+	if err != nil {
+		return filter, err
+	}
+	filter = Filter{
+		Nodes:  make(map[string]struct{}),
+		Apps:   make(map[string]struct{}),
+		Chains: make(map[string]struct{}),
+	}
+	for _, n := range nodes {
+		filter.Nodes[strings.ToLower(n)] = struct{}{}
+	}
+	for _, a := range apps {
+		address, err := normalizeAppAddress(a)
+		if err != nil {
+			return filter, err
+		}
+		filter.Apps[address] = struct{}{}
+	}
+	for _, c := range chains {
+		filter.Chains[c] = struct{}{}
+	}
+	return filter, nil
+}
+
+func (f Filter) IsEmpty() bool {
+	return len(f.Nodes) == 0 && len(f.Apps) == 0 && len(f.Chains) == 0
+}
+
+// Returns true if the claim of the node, app and chain is within the filter
+func (f Filter) Match(nodeAddress, appAddress, chainID string) bool {
+	if len(f.Nodes) != 0 {
+		if _, ok := f.Nodes[strings.ToLower(nodeAddress)]; !ok {
+			return false
+		}
+	}
+	if len(f.Apps) != 0 {
+		if _, ok := f.Apps[strings.ToLower(appAddress)]; !ok {
+			return false
+		}
+	}
+	if len(f.Chains) != 0 {
+		if _, ok := f.Chains[chainID]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func NewFilteredReport(f Filter) *FilteredReport {
+	return &FilteredReport{
+		Nodes:         sortedKeys(f.Nodes),
+		Apps:          sortedKeys(f.Apps),
+		Chains:        sortedKeys(f.Chains),
+		RelaysByChain: make(map[string]int64),
+	}
+}
+
+// Merges the inline values with the values of the list file (one per line, # for comments)
+func loadFilterList(inline []string, file string) ([]string, error) {
+	values := make([]string, 0, len(inline))
+	for _, v := range inline {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	if file == "" {
+		return values, nil
+	}
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, NewFilterFileError(file, err)
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		values = append(values, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, NewFilterFileError(file, err)
+	}
+	return values, nil
+}
+
+func GetAddressFromPubKey(pkHex string) (string, error) {
+	apk, err := crypto.NewPublicKey(pkHex)
+	if err != nil {
+		return "", NewPublicKeyError(pkHex)
+	}
+	return apk.Address().String(), nil
+}
+
+// Apps may be given by public key, the claims are matched by address
+func normalizeAppAddress(a string) (string, error) {
+	if len(a) == PublicKeyHexLength {
+		address, err := GetAddressFromPubKey(a)
+		if err != nil {
+			return "", err
+		}
+		a = address
+	}
+	return strings.ToLower(a), nil
+}
+
+func sortedKeys(m map[string]struct{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
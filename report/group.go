@@ -0,0 +1,76 @@
+package report
+
+import (
+	"sort"
+	"strings"
+)
+
+type Group struct {
+	Nodes []string `json:"nodes"`
+	Apps  []string `json:"apps"`
+}
+
+type GroupReport struct {
+	Nodes                    []string         `json:"nodes"`
+	Apps                     []string         `json:"apps"`
+	TotalRelays              int64            `json:"total_relays"`
+	TotalChallengesCompleted int64            `json:"total_challenges_completed"`
+	ReportByChain            map[string]int64 `json:"report_by_chain"`
+}
+
+type groupSet struct {
+	nodes map[string]struct{}
+	apps  map[string]struct{}
+}
+
+// The named groups of node and app addresses aggregated in the report
+type Groups map[string]groupSet
+
+func NewGroups(groups map[string]Group) (Groups, error) {
+	rg := make(Groups, len(groups))
+	for name, g := range groups {
+		set := groupSet{
+			nodes: make(map[string]struct{}),
+			apps:  make(map[string]struct{}),
+		}
+		for _, n := range g.Nodes {
+			set.nodes[strings.ToLower(strings.TrimSpace(n))] = struct{}{}
+		}
+		for _, a := range g.Apps {
+			address, err := normalizeAppAddress(strings.TrimSpace(a))
+			if err != nil {
+				return nil, NewInvalidGroupError(name, err)
+			}
+			set.apps[address] = struct{}{}
+		}
+		rg[name] = set
+	}
+	return rg, nil
+}
+
+// Returns the names of the groups containing either the node or the app, in order
+func (rg Groups) Match(nodeAddress, appAddress string) (names []string) {
+	nodeAddress, appAddress = strings.ToLower(nodeAddress), strings.ToLower(appAddress)
+	for name, g := range rg {
+		_, nodeOk := g.nodes[nodeAddress]
+		_, appOk := g.apps[appAddress]
+		if nodeOk || appOk {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return
+}
+
+// Creates an empty report for every group, so groups without relays are still listed
+func (rg Groups) NewGroupReports() map[string]GroupReport {
+	reports := make(map[string]GroupReport, len(rg))
+	for name, g := range rg {
+		reports[name] = GroupReport{
+			Nodes:         sortedKeys(g.nodes),
+			Apps:          sortedKeys(g.apps),
+			ReportByChain: make(map[string]int64),
+		}
+	}
+	return reports
+}
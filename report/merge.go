@@ -0,0 +1,188 @@
+package report
+
+import (
+	"reflect"
+	"sort"
+)
+
+const (
+	SelectorMerge = "merge"
+)
+
+type reportFile struct {
+	file   string
+	report Report
+}
+
+// Merges the report files into one, the block ranges must be contiguous and the filters identical
+func MergeFiles(files []string) (result Report, err error) {
+	reports := make([]Report, 0, len(files))
+	for _, file := range files {
+		r, err := ReadFile(file)
+		if err != nil {
+			return result, err
+		}
+		reports = append(reports, r)
+	}
+	return MergeNamed(files, reports)
+}
+
+// Merges the reports into one like MergeFiles, the names identify the reports in the errors and are the source files of the result
+func MergeNamed(names []string, sources []Report) (result Report, err error) {
+	reports := make([]reportFile, 0, len(sources))
+	for i, r := range sources {
+		reports = append(reports, reportFile{file: names[i], report: r})
+	}
+	sort.SliceStable(reports, func(i, j int) bool {
+		return reports[i].report.BlockReport.MinHeight < reports[j].report.BlockReport.MinHeight
+	})
+	merged := make([]Report, 0, len(reports))
+	sourceFiles := make([]string, 0, len(reports))
+	for i, r := range reports {
+		if i != 0 {
+			prev := reports[i-1]
+			// ranges exclude the max height, so the next report starts where the previous one ends
+			if r.report.BlockReport.MinHeight < prev.report.BlockReport.MaxHeight {
+				return result, NewOverlappingReportsError(prev.file, prev.report.BlockReport, r.file, r.report.BlockReport)
+			}
+			if r.report.BlockReport.MinHeight > prev.report.BlockReport.MaxHeight {
+				return result, NewNonContiguousReportsError(prev.file, prev.report.BlockReport, r.file, r.report.BlockReport)
+			}
+			if !sameFilters(prev.report.Filtered, r.report.Filtered) {
+				return result, NewMismatchedFiltersError(prev.file, r.file)
+			}
+		}
+		merged = append(merged, r.report)
+		sourceFiles = append(sourceFiles, r.file)
+	}
+	result = Merge(merged...)
+	result.BlockSelector = SelectorMerge
+	result.SourceFiles = sourceFiles
+	result.Metadata = MergeRunMetadata(merged)
+	return result, nil
+}
+
+func sameFilters(a, b *FilteredReport) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return reflect.DeepEqual(a.Nodes, b.Nodes) && reflect.DeepEqual(a.Apps, b.Apps) && reflect.DeepEqual(a.Chains, b.Chains)
+}
+
+// Returns a new report with the counters and maps of all the reports summed and the block range spanning all of them
+func Merge(reports ...Report) Report {
+	result := New("", BlockReport{})
+	for i, r := range reports {
+		if i == 0 {
+			result.BlockSelector = r.BlockSelector
+			result.BlockReport = r.BlockReport
+		}
+		Add(&result, r)
+	}
+	return result
+}
+
+// Adds the counters and maps of src to dst, dst keeps no reference to the maps and slices of src
+func Add(dst *Report, src Report) {
+	dst.TotalRelaysCompleted += src.TotalRelaysCompleted
+	dst.TotalChallengesCompleted += src.TotalChallengesCompleted
+	dst.TotalMinted += src.TotalMinted
+	dst.TotalGoodTxs += src.TotalGoodTxs
+	dst.TotalBadTxs += src.TotalBadTxs
+	dst.TotalProofTxs += src.TotalProofTxs
+	if dst.BadTxsMap == nil {
+		dst.BadTxsMap = make(map[uint32]int64)
+	}
+	for code, count := range src.BadTxsMap {
+		dst.BadTxsMap[code] += count
+	}
+	if dst.NodeReports == nil {
+		dst.NodeReports = make(map[string]NodeReport)
+	}
+	for address, nodeReport := range src.NodeReports {
+		cur, found := dst.NodeReports[address]
+		if !found {
+			cur = NewNodeReport()
+		}
+		cur.TotalRelays += nodeReport.TotalRelays
+		cur.Service = append(cur.Service, nodeReport.Service...)
+		addChainCounts(cur.ServiceReportByChain, nodeReport.ServiceReportByChain)
+		dst.NodeReports[address] = cur
+	}
+	if dst.AppReports == nil {
+		dst.AppReports = make(map[string]AppReport)
+	}
+	for address, appReport := range src.AppReports {
+		cur, found := dst.AppReports[address]
+		if !found {
+			cur = NewAppReport()
+		}
+		cur.TotalRelays += appReport.TotalRelays
+		cur.ServicedBy = append(cur.ServicedBy, appReport.ServicedBy...)
+		addChainCounts(cur.ServicedReportByChain, appReport.ServicedReportByChain)
+		dst.AppReports[address] = cur
+	}
+	if src.Filtered != nil {
+		if dst.Filtered == nil {
+			dst.Filtered = &FilteredReport{
+				Nodes:         copyStrings(src.Filtered.Nodes),
+				Apps:          copyStrings(src.Filtered.Apps),
+				Chains:        copyStrings(src.Filtered.Chains),
+				RelaysByChain: make(map[string]int64),
+			}
+		}
+		dst.Filtered.TotalRelaysCompleted += src.Filtered.TotalRelaysCompleted
+		dst.Filtered.TotalChallengesCompleted += src.Filtered.TotalChallengesCompleted
+		dst.Filtered.TotalProofTxs += src.Filtered.TotalProofTxs
+		addChainCounts(dst.Filtered.RelaysByChain, src.Filtered.RelaysByChain)
+	}
+	if len(src.GroupReports) != 0 && dst.GroupReports == nil {
+		dst.GroupReports = make(map[string]GroupReport)
+	}
+	for name, groupReport := range src.GroupReports {
+		cur, found := dst.GroupReports[name]
+		if !found {
+			cur = GroupReport{
+				Nodes:         copyStrings(groupReport.Nodes),
+				Apps:          copyStrings(groupReport.Apps),
+				ReportByChain: make(map[string]int64),
+			}
+		}
+		cur.TotalRelays += groupReport.TotalRelays
+		cur.TotalChallengesCompleted += groupReport.TotalChallengesCompleted
+		addChainCounts(cur.ReportByChain, groupReport.ReportByChain)
+		dst.GroupReports[name] = cur
+	}
+	if len(src.FailedHeights) != 0 {
+		AddFailedHeights(dst, src.FailedHeights)
+	}
+	dst.Incomplete = dst.Incomplete || src.Incomplete
+	if src.BlockReport.MinHeight < dst.BlockReport.MinHeight {
+		dst.BlockReport.MinHeight = src.BlockReport.MinHeight
+	}
+	if src.BlockReport.MaxHeight > dst.BlockReport.MaxHeight {
+		dst.BlockReport.MaxHeight = src.BlockReport.MaxHeight
+	}
+}
+
+// Returns the relays of every chain, summed over the node reports
+func RelaysByChain(result Report) map[string]int64 {
+	chains := make(map[string]int64)
+	for _, nodeReport := range result.NodeReports {
+		addChainCounts(chains, nodeReport.ServiceReportByChain)
+	}
+	return chains
+}
+
+func copyStrings(s []string) []string {
+	if s == nil {
+		return nil
+	}
+	return append(make([]string, 0, len(s)), s...)
+}
+
+func addChainCounts(dst, src map[string]int64) {
+	for chain, count := range src {
+		dst[chain] += count
+	}
+}
@@ -0,0 +1,158 @@
+package report
+
+import (
+	"reflect"
+	"testing"
+)
+
+func rangeReport(minHeight, maxHeight, relays int64, filtered *FilteredReport) Report {
+	r := New("timeline", BlockReport{MinHeight: minHeight, MaxHeight: maxHeight})
+	r.TotalRelaysCompleted = relays
+	r.Filtered = filtered
+	return r
+}
+
+func TestMergeNamed(t *testing.T) {
+	filter := func(chains ...string) *FilteredReport {
+		return &FilteredReport{Chains: chains, RelaysByChain: map[string]int64{}}
+	}
+	tests := []struct {
+		name        string
+		names       []string
+		reports     []Report
+		err         error
+		blockReport BlockReport
+		sourceFiles []string
+		relays      int64
+	}{
+		{
+			name:        "contiguous ranges are merged in height order",
+			names:       []string{"b.json", "a.json", "c.json"},
+			reports:     []Report{rangeReport(20, 30, 2, nil), rangeReport(10, 20, 1, nil), rangeReport(30, 40, 4, nil)},
+			blockReport: BlockReport{MinHeight: 10, MaxHeight: 40},
+			sourceFiles: []string{"a.json", "b.json", "c.json"},
+			relays:      7,
+		},
+		{
+			name:        "a single report",
+			names:       []string{"a.json"},
+			reports:     []Report{rangeReport(10, 20, 1, filter("0001"))},
+			blockReport: BlockReport{MinHeight: 10, MaxHeight: 20},
+			sourceFiles: []string{"a.json"},
+			relays:      1,
+		},
+		{
+			name:    "overlapping ranges",
+			names:   []string{"a.json", "b.json"},
+			reports: []Report{rangeReport(10, 21, 1, nil), rangeReport(20, 30, 2, nil)},
+			err:     NewOverlappingReportsError("a.json", BlockReport{MinHeight: 10, MaxHeight: 21}, "b.json", BlockReport{MinHeight: 20, MaxHeight: 30}),
+		},
+		{
+			name:    "identical ranges",
+			names:   []string{"a.json", "b.json"},
+			reports: []Report{rangeReport(10, 20, 1, nil), rangeReport(10, 20, 2, nil)},
+			err:     NewOverlappingReportsError("a.json", BlockReport{MinHeight: 10, MaxHeight: 20}, "b.json", BlockReport{MinHeight: 10, MaxHeight: 20}),
+		},
+		{
+			name:    "a gap between the ranges",
+			names:   []string{"b.json", "a.json"},
+			reports: []Report{rangeReport(21, 30, 2, nil), rangeReport(10, 20, 1, nil)},
+			err:     NewNonContiguousReportsError("a.json", BlockReport{MinHeight: 10, MaxHeight: 20}, "b.json", BlockReport{MinHeight: 21, MaxHeight: 30}),
+		},
+		{
+			name:    "different filters",
+			names:   []string{"a.json", "b.json"},
+			reports: []Report{rangeReport(10, 20, 1, filter("0001")), rangeReport(20, 30, 2, filter("0002"))},
+			err:     NewMismatchedFiltersError("a.json", "b.json"),
+		},
+		{
+			name:    "a filtered and an unfiltered report",
+			names:   []string{"a.json", "b.json"},
+			reports: []Report{rangeReport(10, 20, 1, filter("0001")), rangeReport(20, 30, 2, nil)},
+			err:     NewMismatchedFiltersError("a.json", "b.json"),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := MergeNamed(tt.names, tt.reports)
+			if tt.err != nil {
+				if err == nil || err.Error() != tt.err.Error() {
+					t.Fatalf("expected error %v, got %v", tt.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result.BlockSelector != SelectorMerge {
+				t.Errorf("expected the %s selector, got %s", SelectorMerge, result.BlockSelector)
+			}
+			if result.BlockReport != tt.blockReport {
+				t.Errorf("expected the range %+v, got %+v", tt.blockReport, result.BlockReport)
+			}
+			if !reflect.DeepEqual(result.SourceFiles, tt.sourceFiles) {
+				t.Errorf("expected the source files %v, got %v", tt.sourceFiles, result.SourceFiles)
+			}
+			if result.TotalRelaysCompleted != tt.relays {
+				t.Errorf("expected %d relays, got %d", tt.relays, result.TotalRelaysCompleted)
+			}
+		})
+	}
+}
+
+// The merged report must not share the maps or slices of its sources
+func TestAddCopies(t *testing.T) {
+	src := rangeReport(10, 20, 5, &FilteredReport{
+		Nodes:         []string{"node1"},
+		Apps:          []string{"app1"},
+		Chains:        []string{"0001"},
+		RelaysByChain: map[string]int64{"0001": 5},
+	})
+	src.NodeReports["node1"] = NodeReport{
+		Service:              []ServiceReport{{Address: "app1", TotalRelays: 5, ChainID: "0001"}},
+		TotalRelays:          5,
+		ServiceReportByChain: map[string]int64{"0001": 5},
+	}
+	src.AppReports["app1"] = AppReport{
+		ServicedBy:            []ServiceReport{{Address: "node1", TotalRelays: 5, ChainID: "0001"}},
+		TotalRelays:           5,
+		ServicedReportByChain: map[string]int64{"0001": 5},
+	}
+	src.GroupReports = map[string]GroupReport{
+		"group": {Nodes: []string{"node1"}, Apps: []string{"app1"}, TotalRelays: 5, ReportByChain: map[string]int64{"0001": 5}},
+	}
+	src.FailedHeights = []HeightError{{Height: 15, Phase: PhaseBlock, Message: "failed"}}
+
+	dst := New("", BlockReport{MinHeight: 10, MaxHeight: 20})
+	Add(&dst, src)
+
+	dst.Filtered.Nodes[0] = "changed"
+	dst.Filtered.Apps[0] = "changed"
+	dst.Filtered.Chains[0] = "changed"
+	dst.Filtered.RelaysByChain["0001"] = 0
+	dst.NodeReports["node1"].Service[0].TotalRelays = 0
+	dst.NodeReports["node1"].ServiceReportByChain["0001"] = 0
+	dst.AppReports["app1"].ServicedBy[0].TotalRelays = 0
+	dst.AppReports["app1"].ServicedReportByChain["0001"] = 0
+	dst.GroupReports["group"].Nodes[0] = "changed"
+	dst.GroupReports["group"].Apps[0] = "changed"
+	dst.GroupReports["group"].ReportByChain["0001"] = 0
+	dst.FailedHeights[0].Height = 0
+
+	if src.Filtered.Nodes[0] != "node1" || src.Filtered.Apps[0] != "app1" || src.Filtered.Chains[0] != "0001" || src.Filtered.RelaysByChain["0001"] != 5 {
+		t.Errorf("the filtered report of the source was modified: %+v", src.Filtered)
+	}
+	if nodeReport := src.NodeReports["node1"]; nodeReport.Service[0].TotalRelays != 5 || nodeReport.ServiceReportByChain["0001"] != 5 {
+		t.Errorf("the node report of the source was modified: %+v", nodeReport)
+	}
+	if appReport := src.AppReports["app1"]; appReport.ServicedBy[0].TotalRelays != 5 || appReport.ServicedReportByChain["0001"] != 5 {
+		t.Errorf("the app report of the source was modified: %+v", appReport)
+	}
+	if groupReport := src.GroupReports["group"]; groupReport.Nodes[0] != "node1" || groupReport.Apps[0] != "app1" || groupReport.ReportByChain["0001"] != 5 {
+		t.Errorf("the group report of the source was modified: %+v", groupReport)
+	}
+	if src.FailedHeights[0].Height != 15 {
+		t.Errorf("the failed heights of the source were modified: %+v", src.FailedHeights)
+	}
+}
@@ -0,0 +1,58 @@
+package report
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+)
+
+// Set at build time with -ldflags "-X github.com/pokt-network/relay_counter/report.Version=<version>"
+var Version = "dev"
+
+type RunMetadata struct {
+	ToolVersion    string     `json:"tool_version"`
+	Endpoint       string     `json:"endpoint,omitempty"`
+	Profile        string     `json:"profile,omitempty"`
+	ConfigHash     string     `json:"config_hash,omitempty"`
+	GeneratedAt    time.Time  `json:"generated_at"`
+	StartBlockTime *time.Time `json:"start_block_time,omitempty"`
+	EndBlockTime   *time.Time `json:"end_block_time,omitempty"`
+}
+
+// Returns the metadata of a merge of the reports, which must be sorted by height
+func MergeRunMetadata(reports []Report) *RunMetadata {
+	metadata := &RunMetadata{
+		ToolVersion: Version,
+		GeneratedAt: time.Now().UTC(),
+	}
+	if len(reports) == 0 {
+		return metadata
+	}
+	if first := reports[0].Metadata; first != nil {
+		metadata.Endpoint = first.Endpoint
+		metadata.Profile = first.Profile
+		metadata.StartBlockTime = first.StartBlockTime
+	}
+	// the profile is kept only when every report was generated with it
+	for _, r := range reports[1:] {
+		if r.Metadata == nil || r.Metadata.Profile != metadata.Profile {
+			metadata.Profile = ""
+			break
+		}
+	}
+	if last := reports[len(reports)-1].Metadata; last != nil {
+		metadata.EndBlockTime = last.EndBlockTime
+	}
+	return metadata
+}
+
+// Returns the sha256 of the json of the processed config, so reports of identical configs can be matched
+func ConfigHash(config interface{}) string {
+	bz, err := json.Marshal(config)
+	if err != nil {
+		return ""
+	}
+	hash := sha256.Sum256(bz)
+	return hex.EncodeToString(hash[:])
+}
@@ -0,0 +1,117 @@
+// Package report holds the relay report, its filters and groups, and the merging, diffing and schema of reports.
+package report
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"sort"
+)
+
+const (
+	// Bump the major version on removed or renamed fields, the minor version on added fields
+	SchemaVersion = "1.2.0"
+)
+
+type BlockReport struct {
+	MinHeight int64 `json:"min_height"`
+	MaxHeight int64 `json:"max_height"`
+}
+
+type Report struct {
+	SchemaVersion            string                 `json:"schema_version"`
+	Metadata                 *RunMetadata           `json:"metadata,omitempty"`
+	TotalRelaysCompleted     int64                  `json:"total_relays_completed"`
+	TotalChallengesCompleted int64                  `json:"total_challenges_completed"`
+	TotalMinted              int64                  `json:"total_minted"`
+	TotalGoodTxs             int64                  `json:"total_good_txs"`
+	TotalBadTxs              int64                  `json:"total_bad_txs"`
+	TotalProofTxs            int64                  `json:"proof_msgs"`
+	BadTxsMap                map[uint32]int64       `json:"bad_txs_count_by_error"`
+	NodeReports              map[string]NodeReport  `json:"node_report"`
+	AppReports               map[string]AppReport   `json:"app_report"`
+	BlockSelector            string                 `json:"selector"`
+	BlockReport              BlockReport            `json:"block_report"`
+	Filtered                 *FilteredReport        `json:"filtered,omitempty"`
+	GroupReports             map[string]GroupReport `json:"group_report,omitempty"`
+	SourceFiles              []string               `json:"source_files,omitempty"`
+	Incomplete               bool                   `json:"incomplete,omitempty"`
+	FailedHeights            []HeightError          `json:"failed_heights,omitempty"`
+}
+
+type ServiceReport struct {
+	Address     string `json:"address"`
+	TotalRelays int64  `json:"total_relays"`
+	ChainID     string `json:"relay_chain"`
+}
+
+type NodeReport struct {
+	Service              []ServiceReport  `json:"serviced"`
+	TotalRelays          int64            `json:"total_relays"`
+	ServiceReportByChain map[string]int64 `json:"service_by_chain"`
+}
+
+type AppReport struct {
+	ServicedBy            []ServiceReport  `json:"serviced_by"`
+	TotalRelays           int64            `json:"total_relays"`
+	ServicedReportByChain map[string]int64 `json:"serviced_by_chain"`
+}
+
+// Returns an empty report of the range with its maps set
+func New(selector string, blockReport BlockReport) Report {
+	return Report{
+		SchemaVersion: SchemaVersion,
+		BadTxsMap:     make(map[uint32]int64),
+		NodeReports:   make(map[string]NodeReport),
+		AppReports:    make(map[string]AppReport),
+		BlockSelector: selector,
+		BlockReport:   blockReport,
+	}
+}
+
+func NewAppReport() AppReport {
+	return AppReport{
+		ServicedBy:            make([]ServiceReport, 0),
+		TotalRelays:           0,
+		ServicedReportByChain: make(map[string]int64),
+	}
+}
+
+func NewNodeReport() NodeReport {
+	return NodeReport{
+		Service:              make([]ServiceReport, 0),
+		TotalRelays:          0,
+		ServiceReportByChain: make(map[string]int64),
+	}
+}
+
+// Marks the report incomplete with the heights that could not be retrieved, sorted by height
+func AddFailedHeights(result *Report, failed []HeightError) {
+	result.FailedHeights = append(result.FailedHeights, failed...)
+	sort.SliceStable(result.FailedHeights, func(i, j int) bool {
+		return result.FailedHeights[i].Height < result.FailedHeights[j].Height
+	})
+	result.Incomplete = len(result.FailedHeights) != 0
+}
+
+// Returns the height an interrupted retrieval stopped at, if any
+func CancelledHeight(failed []HeightError) (int64, bool) {
+	for _, f := range failed {
+		if f.Phase == PhaseCancelled {
+			return f.Height, true
+		}
+	}
+	return 0, false
+}
+
+// Reads a report previously written as json
+func ReadFile(file string) (result Report, err error) {
+	fBz, err := ioutil.ReadFile(file)
+	if err != nil {
+		return result, err
+	}
+	err = json.Unmarshal(fBz, &result)
+	if err != nil {
+		return result, NewInvalidReportFileError(file, err)
+	}
+	return result, nil
+}
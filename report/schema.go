@@ -0,0 +1,194 @@
+package report
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	SchemaFile      = "schema/report.schema.json"
+	jsonSchemaDraft = "http://json-schema.org/draft-07/schema#"
+)
+
+// The documentation of every field of the report, keyed by <type>.<json name>
+var reportFieldDescriptions = map[string]string{
+	"Report.schema_version":             "Version of this schema the report follows.",
+	"Report.metadata":                   "How and when the report was generated.",
+	"Report.total_relays_completed":     "Network wide relays of the relay claims proven in the range.",
+	"Report.total_challenges_completed": "Network wide challenges of the challenge claims proven in the range.",
+	"Report.total_minted":               "Supply at the end of the range minus the supply at the start of the range.",
+	"Report.total_good_txs":             "Successful transactions in the range.",
+	"Report.total_bad_txs":              "Failed transactions in the range.",
+	"Report.proof_msgs":                 "Successful proof transactions in the range.",
+	"Report.bad_txs_count_by_error":     "Failed transactions by result code.",
+	"Report.node_report":                "Relays serviced by every node, keyed by node address.",
+	"Report.app_report":                 "Relays consumed by every app, keyed by app address.",
+	"Report.selector":                   "Method used to select the range: timeline, byBlock, period (the previous complete period of a scheduled job), watch, serve, merge, or batch for the combined report of a batch.",
+	"Report.block_report":               "Heights of the range.",
+	"Report.filtered":                   "Totals of the claims matching the filters, only set when filters are used.",
+	"Report.group_report":               "Totals of every configured group, keyed by group name.",
+	"Report.source_files":               "Report files combined by the merge command, or ranges combined by a batch.",
+	"Report.incomplete":                 "Set when some heights could not be retrieved or processed, the totals leave them out.",
+	"Report.failed_heights":             "Heights that could not be retrieved or processed, sorted by height.",
+
+	"HeightError.height": "Height that failed.",
+	"HeightError.phase":  "Step that failed: block, blocktxs, claims, process or supply, or cancelled when the run was interrupted at the height and the range cut short before it.",
+	"HeightError.error":  "Error of the last attempt.",
+
+	"RunMetadata.tool_version":     "Version of relay_counter that generated the report.",
+	"RunMetadata.endpoint":         "Pocket endpoint the chain data was retrieved from.",
+	"RunMetadata.profile":          "Config profile the report was generated with, kept by a merge only when every report shares it.",
+	"RunMetadata.config_hash":      "SHA-256 of the processed config.",
+	"RunMetadata.generated_at":     "Time the report was generated.",
+	"RunMetadata.start_block_time": "Time of the first block of the range.",
+	"RunMetadata.end_block_time":   "Time of the last block of the range.",
+
+	"BlockReport.min_height": "First height of the range.",
+	"BlockReport.max_height": "Height the range ends at, not included in the range.",
+
+	"NodeReport.serviced":         "Every claim of the node, the address is the address of the app serviced.",
+	"NodeReport.total_relays":     "Relays serviced by the node.",
+	"NodeReport.service_by_chain": "Relays serviced by the node, keyed by relay chain id.",
+
+	"AppReport.serviced_by":       "Every claim for the app, the address is the address of the node that serviced it.",
+	"AppReport.total_relays":      "Relays consumed by the app.",
+	"AppReport.serviced_by_chain": "Relays consumed by the app, keyed by relay chain id.",
+
+	"ServiceReport.address":      "Address of the node or the app on the other side of the claim.",
+	"ServiceReport.total_relays": "Relays of the claim.",
+	"ServiceReport.relay_chain":  "Relay chain id of the claim.",
+
+	"FilteredReport.nodes":                      "Node addresses filtered on.",
+	"FilteredReport.apps":                       "App addresses filtered on.",
+	"FilteredReport.chains":                     "Relay chain ids filtered on.",
+	"FilteredReport.total_relays_completed":     "Relays of the matching relay claims.",
+	"FilteredReport.total_challenges_completed": "Challenges of the matching challenge claims.",
+	"FilteredReport.proof_msgs":                 "Proof transactions of the matching claims.",
+	"FilteredReport.relays_by_chain":            "Relays of the matching relay claims, keyed by relay chain id.",
+
+	"GroupReport.nodes":                      "Node addresses of the group.",
+	"GroupReport.apps":                       "App addresses of the group.",
+	"GroupReport.total_relays":               "Relays of the claims of the group members.",
+	"GroupReport.total_challenges_completed": "Challenges of the claims of the group members.",
+	"GroupReport.report_by_chain":            "Relays of the claims of the group members, keyed by relay chain id.",
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// Generates the JSON schema of the Report from its types, every field must be documented in reportFieldDescriptions
+func GenerateSchema() (map[string]interface{}, error) {
+	definitions := make(map[string]interface{})
+	root, err := schemaOf(reflect.TypeOf(Report{}), definitions)
+	if err != nil {
+		return nil, err
+	}
+	schema := map[string]interface{}{
+		"$schema":     jsonSchemaDraft,
+		"$id":         "https://github.com/pokt-network/relay_counter/" + SchemaFile,
+		"title":       "relay_counter report",
+		"description": "Report schema version " + SchemaVersion,
+		"definitions": definitions,
+		"allOf":       []interface{}{root},
+	}
+	return schema, nil
+}
+
+func schemaOf(t reflect.Type, definitions map[string]interface{}) (map[string]interface{}, error) {
+	if t == timeType {
+		return map[string]interface{}{"type": "string", "format": "date-time"}, nil
+	}
+	switch t.Kind() {
+	case reflect.Ptr:
+		return schemaOf(t.Elem(), definitions)
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return map[string]interface{}{"type": "integer"}, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer", "minimum": 0}, nil
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}, nil
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}, nil
+	case reflect.Slice:
+		items, err := schemaOf(t.Elem(), definitions)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"type": []string{"array", "null"}, "items": items}, nil
+	case reflect.Map:
+		values, err := schemaOf(t.Elem(), definitions)
+		if err != nil {
+			return nil, err
+		}
+		s := map[string]interface{}{"type": []string{"object", "null"}, "additionalProperties": values}
+		// integer keys are encoded as strings
+		if t.Key().Kind() != reflect.String {
+			s["propertyNames"] = map[string]interface{}{"pattern": "^[0-9]+$"}
+		}
+		return s, nil
+	case reflect.Struct:
+		ref := map[string]interface{}{"$ref": "#/definitions/" + t.Name()}
+		if _, ok := definitions[t.Name()]; ok {
+			return ref, nil
+		}
+		// reserve the name before recursing
+		definitions[t.Name()] = nil
+		properties := make(map[string]interface{})
+		required := make([]string, 0)
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			tag := strings.Split(f.Tag.Get("json"), ",")
+			name := tag[0]
+			if name == "-" || f.PkgPath != "" {
+				continue
+			}
+			if name == "" {
+				name = f.Name
+			}
+			description, ok := reportFieldDescriptions[t.Name()+"."+name]
+			if !ok {
+				return nil, NewUndocumentedFieldError(t.Name(), name)
+			}
+			property, err := schemaOf(f.Type, definitions)
+			if err != nil {
+				return nil, err
+			}
+			if _, isRef := property["$ref"]; isRef {
+				// draft-07 ignores the siblings of $ref
+				property = map[string]interface{}{"allOf": []interface{}{property}}
+			}
+			property["description"] = description
+			properties[name] = property
+			omitempty := len(tag) > 1 && tag[1] == "omitempty"
+			if !omitempty {
+				required = append(required, name)
+			}
+		}
+		sort.Strings(required)
+		definitions[t.Name()] = map[string]interface{}{
+			"type":       "object",
+			"properties": properties,
+			"required":   required,
+		}
+		return ref, nil
+	default:
+		return nil, NewUnsupportedSchemaTypeError(t.String())
+	}
+}
+
+// Returns the indented json of the schema, as shipped in SchemaFile
+func MarshalSchema() ([]byte, error) {
+	schema, err := GenerateSchema()
+	if err != nil {
+		return nil, err
+	}
+	bz, err := json.MarshalIndent(schema, "", "    ")
+	if err != nil {
+		return nil, err
+	}
+	return append(bz, '\n'), nil
+}
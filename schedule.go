@@ -0,0 +1,474 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pokt-network/relay_counter/client"
+	"github.com/pokt-network/relay_counter/indexer"
+	"github.com/pokt-network/relay_counter/logging"
+	"github.com/pokt-network/relay_counter/notify"
+	"github.com/pokt-network/relay_counter/report"
+	"github.com/pokt-network/relay_counter/selector"
+	"github.com/robfig/cron/v3"
+)
+
+const (
+	CommandSchedule        = "schedule"
+	DefaultScheduleStatus  = "result/schedule_status.json"
+	DefaultScheduleResults = "result"
+	PeriodHour             = "hour"
+	PeriodDay              = "day"
+	PeriodWeek             = "week"
+	PeriodMonth            = "month"
+	// the time in the name of the results files, without the colons some file systems reject
+	scheduleFileTimeLayout = "2006-01-02T15-04"
+)
+
+// The jobs run by the schedule command
+type ScheduleConfig struct {
+	StatusFile string        `json:"status_file,omitempty"`
+	Jobs       []ScheduleJob `json:"jobs"`
+}
+
+// A report generated on a cron schedule. The range is the previous complete period when Period is set, the selection of
+// the job when Selector is set, or the selection of the config. The filters of the job replace those of the config
+type ScheduleJob struct {
+	Name       string             `json:"name"`
+	Cron       string             `json:"cron"`
+	Period     string             `json:"period,omitempty"`
+	Selector   string             `json:"selector,omitempty"`
+	Timeline   *selector.Timeline `json:"timeline,omitempty"`
+	ByBlock    *selector.ByBlock  `json:"byBlock,omitempty"`
+	Filters    *report.Filters    `json:"filters,omitempty"`
+	Format     string             `json:"format,omitempty"`
+	ResultsDir string             `json:"results_dir,omitempty"`
+	Retention  Retention          `json:"retention,omitempty"`
+}
+
+// The reports of a job kept in its results directory, 0 keeps them all
+type Retention struct {
+	MaxAge     string `json:"max_age,omitempty"`
+	MaxReports int    `json:"max_reports,omitempty"`
+}
+
+// The outcome of the last runs of a job, saved to the status file after every run
+type JobStatus struct {
+	LastRun     *time.Time          `json:"last_run,omitempty"`
+	LastSuccess *time.Time          `json:"last_success,omitempty"`
+	LastError   string              `json:"last_error,omitempty"`
+	LastRange   *report.BlockReport `json:"last_range,omitempty"`
+	LastFile    string              `json:"last_file,omitempty"`
+	NextRun     time.Time           `json:"next_run"`
+}
+
+func (s ScheduleConfig) Validate(params selector.Params) (errs []error) {
+	if len(s.Jobs) == 0 {
+		errs = append(errs, NewMissingScheduleJobsError())
+	}
+	names := make(map[string]bool)
+	for i, job := range s.Jobs {
+		name := job.Name
+		switch {
+		case name == "":
+			name = fmt.Sprintf("#%d", i+1)
+			errs = append(errs, NewMissingJobNameError(i+1))
+		case names[name]:
+			errs = append(errs, NewDuplicateJobError(name))
+		case strings.ContainsAny(name, `/\`):
+			errs = append(errs, NewInvalidJobNameError(name))
+		}
+		names[name] = true
+		for _, err := range job.Validate(params) {
+			errs = append(errs, NewInvalidJobError(name, err))
+		}
+	}
+	return errs
+}
+
+func (job ScheduleJob) Validate(params selector.Params) (errs []error) {
+	if _, err := cron.ParseStandard(job.Cron); err != nil {
+		errs = append(errs, NewInvalidCronError(job.Cron, err))
+	}
+	switch job.Period {
+	case "", PeriodHour, PeriodDay, PeriodWeek, PeriodMonth:
+	default:
+		errs = append(errs, NewInvalidSchedulePeriodError(job.Period))
+	}
+	if job.Period != "" && job.Selector != "" {
+		errs = append(errs, NewPeriodAndSelectorError())
+	}
+	if job.Selector != "" {
+		errs = append(errs, job.selectorOptions(params).Validate()...)
+	}
+	if job.Filters != nil {
+		if _, err := report.NewFilter(*job.Filters); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if job.Format != "" && !isValidFormat(job.Format) {
+		errs = append(errs, NewInvalidFormatError(job.Format))
+	}
+	if job.Retention.MaxAge != "" {
+		if maxAge, err := time.ParseDuration(job.Retention.MaxAge); err != nil || maxAge <= 0 {
+			errs = append(errs, NewInvalidRetentionError("max_age", job.Retention.MaxAge))
+		}
+	}
+	if job.Retention.MaxReports < 0 {
+		errs = append(errs, NewInvalidRetentionError("max_reports", job.Retention.MaxReports))
+	}
+	return errs
+}
+
+func (job ScheduleJob) selectorOptions(params selector.Params) selector.Options {
+	opts := selector.Options{Selector: job.Selector, Params: params}
+	if job.Timeline != nil {
+		opts.Timeline = *job.Timeline
+	}
+	if job.ByBlock != nil {
+		opts.ByBlock = *job.ByBlock
+	}
+	return opts
+}
+
+func (job ScheduleJob) format() string {
+	if job.Format == "" {
+		return FormatJSON
+	}
+	return strings.ToLower(job.Format)
+}
+
+func (job ScheduleJob) resultsDir() string {
+	if job.ResultsDir == "" {
+		return DefaultScheduleResults
+	}
+	return job.ResultsDir
+}
+
+// The results file of a run, named after the start of its period or the time it ran, with the extension of the format.
+// The runs of a job share its sqlite database, the csv files of a run replace the extension of its results file
+func (job ScheduleJob) resultFile(stamp time.Time) string {
+	switch job.format() {
+	case FormatSQLite:
+		return filepath.Join(job.resultsDir(), job.Name+filepath.Ext(DefaultSQLiteFile))
+	case FormatCSV:
+		return filepath.Join(job.resultsDir(), job.Name+"-"+stamp.UTC().Format(scheduleFileTimeLayout)+".csv")
+	}
+	return filepath.Join(job.resultsDir(), job.Name+"-"+stamp.UTC().Format(scheduleFileTimeLayout)+".json")
+}
+
+// The json report the next run of the job is compared to by the alert rules. The csv and sqlite jobs write no json
+// report, a copy of their last report is kept next to their results
+func (job ScheduleJob) previousReportFile(status JobStatus) string {
+	switch job.format() {
+	case FormatCSV, FormatSQLite:
+		return filepath.Join(job.resultsDir(), job.Name+".last.json")
+	}
+	return status.LastFile
+}
+
+// The previous complete period before t, in UTC. Weeks start on Monday
+func previousPeriod(period string, t time.Time) selector.Period {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	switch period {
+	case PeriodHour:
+		end := t.Truncate(time.Hour)
+		return selector.Period{Start: end.Add(-time.Hour), End: end}
+	case PeriodWeek:
+		end := day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+		return selector.Period{Start: end.AddDate(0, 0, -7), End: end}
+	case PeriodMonth:
+		end := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+		return selector.Period{Start: end.AddDate(0, -1, 0), End: end}
+	default:
+		return selector.Period{Start: day.AddDate(0, 0, -1), End: day}
+	}
+}
+
+// Runs the jobs of the config on their schedules until the process is stopped
+func runSchedule(args []string) {
+	fs := flag.NewFlagSet(os.Args[0]+" "+CommandSchedule, flag.ExitOnError)
+	runNow := fs.Bool("runNow", false, "runs every job once at startup, then follows the schedules")
+	cf := newConfigFlags(fs)
+	lf := newLogFlags(fs)
+	_ = fs.Parse(args)
+	lf.apply()
+
+	c, err := cf.load()
+	if err != nil {
+		fatal(err)
+	}
+	if c.Schedule == nil {
+		fatal(NewMissingScheduleError(cf.source()))
+	}
+	setRunNotifier(c, CommandSchedule)
+
+	ctx := interruptContext()
+	pocket := c.Client()
+	logging.Info("Testing the pocket endpoint", "endpoint", pocket.Endpoint())
+	if err := pocket.Test(ctx); err != nil {
+		fatal(err)
+	}
+
+	s := newScheduler(c, pocket)
+	now := time.Now()
+	for _, job := range c.Schedule.Jobs {
+		next := s.schedules[job.Name].Next(now.UTC())
+		if *runNow {
+			next = now
+		}
+		s.setNext(job.Name, next)
+		logging.Info("Scheduled", "job", job.Name, "cron", job.Cron, "next_run", next.Format(time.RFC3339))
+	}
+	s.saveStatus()
+	for {
+		job, at := s.nextJob()
+		if !sleepContext(ctx, time.Until(at)) {
+			break
+		}
+		s.run(ctx, job, at)
+		if ctx.Err() != nil {
+			break
+		}
+		s.setNext(job.Name, s.schedules[job.Name].Next(time.Now().UTC()))
+		s.saveStatus()
+	}
+	s.saveStatus()
+	logging.Info("Interrupted, the scheduler stopped")
+}
+
+type scheduler struct {
+	config    Config
+	client    *client.Client
+	schedules map[string]cron.Schedule
+	status    map[string]JobStatus
+	file      string
+}
+
+// Loads the status of the previous runs, so the last successes survive restarts
+func newScheduler(c Config, pocket *client.Client) *scheduler {
+	s := &scheduler{
+		config:    c,
+		client:    pocket,
+		schedules: make(map[string]cron.Schedule),
+		status:    make(map[string]JobStatus),
+		file:      c.Schedule.StatusFile,
+	}
+	if s.file == "" {
+		s.file = DefaultScheduleStatus
+	}
+	for _, job := range c.Schedule.Jobs {
+		// checked by the validation of the config
+		s.schedules[job.Name], _ = cron.ParseStandard(job.Cron)
+	}
+	if bz, err := ioutil.ReadFile(s.file); err == nil {
+		if err := json.Unmarshal(bz, &s.status); err != nil {
+			logging.Error("Could not read the status file, starting over", "file", s.file, "err", err.Error())
+		}
+	}
+	// the jobs removed from the config
+	for name := range s.status {
+		if _, ok := s.schedules[name]; !ok {
+			delete(s.status, name)
+		}
+	}
+	return s
+}
+
+func (s *scheduler) setNext(name string, next time.Time) {
+	status := s.status[name]
+	status.NextRun = next.UTC()
+	s.status[name] = status
+}
+
+// The job due first, the earlier job of the config on a tie
+func (s *scheduler) nextJob() (job ScheduleJob, at time.Time) {
+	for i, j := range s.config.Schedule.Jobs {
+		if next := s.status[j.Name].NextRun; i == 0 || next.Before(at) {
+			job, at = j, next
+		}
+	}
+	return
+}
+
+// Generates the report of the job and applies its retention, the outcome is recorded in the status
+func (s *scheduler) run(ctx context.Context, job ScheduleJob, at time.Time) {
+	logging.Info("Running the job", "job", job.Name, "scheduled_at", at.UTC().Format(time.RFC3339))
+	status := s.status[job.Name]
+	ranAt := time.Now().UTC()
+	status.LastRun = &ranAt
+	// the report of the previous run, for the alert rules comparing the reports
+	var previous *report.Report
+	if s.config.Alerts.comparesReports() {
+		previous = s.previousReport(job, status)
+	}
+	result, file, err := s.generate(ctx, job, at)
+	if result.BlockReport.MaxHeight != 0 {
+		status.LastRange = &result.BlockReport
+	}
+	if file != "" {
+		status.LastFile = file
+	}
+	var e notify.Event
+	switch {
+	case err != nil && file != "":
+		logging.Error("The job wrote an incomplete report", "job", job.Name, "file", file, "err", err.Error())
+		status.LastError = err.Error()
+		e = notify.Incomplete(CommandSchedule, result, file, err)
+	case err != nil:
+		logging.Error("The job failed", "job", job.Name, "err", err.Error())
+		status.LastError = err.Error()
+		e = notify.Failure(CommandSchedule, err)
+	default:
+		logging.Info("The job succeeded", "job", job.Name, "file", file)
+		status.LastSuccess, status.LastError = &ranAt, ""
+		e = notify.Success(CommandSchedule, result, file)
+	}
+	s.status[job.Name] = status
+	e.Job = job.Name
+	notifyRun(e)
+	if file != "" {
+		if previousFile := job.previousReportFile(status); previousFile != file && s.config.Alerts.comparesReports() {
+			writeResultFile(result, previousFile)
+		}
+		raiseAlerts(s.config, CommandSchedule, job.Name, result, previous, file)
+	}
+	applyRetention(job, time.Now())
+}
+
+// The report of the previous run of the job, nil on its first run or when it can't be read
+func (s *scheduler) previousReport(job ScheduleJob, status JobStatus) *report.Report {
+	if status.LastFile == "" {
+		return nil
+	}
+	file := job.previousReportFile(status)
+	previous, err := readPreviousReport(file)
+	if err != nil {
+		logging.Error("Could not read the previous report, the change rules are skipped", "job", job.Name, "file", file, "err", err.Error())
+		return nil
+	}
+	return previous
+}
+
+// Writes the report of the job, the report of an incomplete run is written too
+func (s *scheduler) generate(ctx context.Context, job ScheduleJob, at time.Time) (result report.Report, file string, err error) {
+	c := s.config
+	c.Schedule, c.Batch = nil, nil
+	if job.Selector != "" {
+		opts := job.selectorOptions(c.Params)
+		c.Selector, c.Timeline, c.ByBlock = opts.Selector, opts.Timeline, opts.ByBlock
+	}
+	if job.Filters != nil {
+		c.Filters = *job.Filters
+	}
+	opts, err := c.IndexerOptions()
+	if err != nil {
+		return result, "", err
+	}
+	stamp := at
+	if job.Period != "" {
+		period := previousPeriod(job.Period, at)
+		opts.Range = selector.Options{Selector: selector.SelectorPeriod, Period: period, Params: c.Params}
+		stamp = period.Start
+	}
+	result, err = indexer.GenerateReport(ctx, s.client, opts)
+	if _, incomplete := err.(*report.IncompleteError); err != nil && !incomplete {
+		return result, "", err
+	}
+	file = job.resultFile(stamp)
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return result, "", err
+	}
+	logging.Info("Writing the report", "job", job.Name, "min_height", result.BlockReport.MinHeight, "max_height", result.BlockReport.MaxHeight, "file", file)
+	writeReport(result, c, file, job.format())
+	return result, file, err
+}
+
+// Writes the status of every job, through a temporary file so a reader never sees a partial status
+func (s *scheduler) saveStatus() {
+	bz, err := json.MarshalIndent(s.status, "", "    ")
+	if err == nil {
+		err = os.MkdirAll(filepath.Dir(s.file), 0755)
+	}
+	if err == nil {
+		err = ioutil.WriteFile(s.file+".tmp", bz, 0644)
+	}
+	if err == nil {
+		err = os.Rename(s.file+".tmp", s.file)
+	}
+	if err != nil {
+		logging.Error("Could not write the status file", "file", s.file, "err", err.Error())
+	}
+}
+
+// The files of a report of a job share the name of the json report up to its extension or csv suffix
+type jobReport struct {
+	files   []string
+	modTime time.Time
+}
+
+// Deletes the reports of the job older than the max age and past the max reports, the newest reports are kept
+func applyRetention(job ScheduleJob, now time.Time) {
+	maxAge, _ := time.ParseDuration(job.Retention.MaxAge)
+	if maxAge == 0 && job.Retention.MaxReports == 0 {
+		return
+	}
+	infos, err := ioutil.ReadDir(job.resultsDir())
+	if err != nil {
+		logging.Error("Could not list the results", "job", job.Name, "dir", job.resultsDir(), "err", err.Error())
+		return
+	}
+	prefix := job.Name + "-"
+	byStamp := make(map[string]*jobReport)
+	for _, info := range infos {
+		name := info.Name()
+		if info.IsDir() || !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		stamp := name[len(prefix):]
+		if i := strings.IndexAny(stamp, "._"); i >= 0 {
+			stamp = stamp[:i]
+		}
+		// the reports of other jobs whose name starts with the name of the job
+		if _, err := time.Parse(scheduleFileTimeLayout, stamp); err != nil {
+			continue
+		}
+		r, ok := byStamp[stamp]
+		if !ok {
+			r = &jobReport{}
+			byStamp[stamp] = r
+		}
+		r.files = append(r.files, filepath.Join(job.resultsDir(), name))
+		if info.ModTime().After(r.modTime) {
+			r.modTime = info.ModTime()
+		}
+	}
+	reports := make([]*jobReport, 0, len(byStamp))
+	for _, r := range byStamp {
+		reports = append(reports, r)
+	}
+	sort.Slice(reports, func(i, j int) bool { return reports[i].modTime.After(reports[j].modTime) })
+	for i, r := range reports {
+		expired := maxAge != 0 && now.Sub(r.modTime) > maxAge
+		extra := job.Retention.MaxReports != 0 && i >= job.Retention.MaxReports
+		if !expired && !extra {
+			continue
+		}
+		for _, file := range r.files {
+			if err := os.Remove(file); err != nil {
+				logging.Error("Could not delete an old report", "job", job.Name, "file", file, "err", err.Error())
+				continue
+			}
+			logging.Info("Deleted an old report", "job", job.Name, "file", file)
+		}
+	}
+}
@@ -0,0 +1,265 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/pokt-network/relay_counter/report"
+	"github.com/pokt-network/relay_counter/selector"
+)
+
+func TestApplyRetention(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	// the stamps of the reports, the report of day i was written i days ago
+	stamp := func(daysAgo int) string {
+		return now.AddDate(0, 0, -daysAgo).Format(scheduleFileTimeLayout)
+	}
+	type file struct {
+		name    string
+		daysAgo int
+	}
+	tests := []struct {
+		name      string
+		job       ScheduleJob
+		files     []file
+		remaining []string
+	}{
+		{
+			name: "keeps the newest reports",
+			job:  ScheduleJob{Name: "daily", Retention: Retention{MaxReports: 2}},
+			files: []file{
+				{"daily-" + stamp(1) + ".json", 1},
+				{"daily-" + stamp(2) + ".json", 2},
+				{"daily-" + stamp(3) + ".json", 3},
+				{"daily-" + stamp(4) + ".json", 4},
+			},
+			remaining: []string{"daily-" + stamp(1) + ".json", "daily-" + stamp(2) + ".json"},
+		},
+		{
+			name: "deletes the reports past the max age",
+			job:  ScheduleJob{Name: "daily", Retention: Retention{MaxAge: "60h"}},
+			files: []file{
+				{"daily-" + stamp(1) + ".json", 1},
+				{"daily-" + stamp(2) + ".json", 2},
+				{"daily-" + stamp(3) + ".json", 3},
+			},
+			remaining: []string{"daily-" + stamp(1) + ".json", "daily-" + stamp(2) + ".json"},
+		},
+		{
+			name: "deletes the markdown summary with its report",
+			job:  ScheduleJob{Name: "daily", Format: FormatMarkdown, Retention: Retention{MaxReports: 1}},
+			files: []file{
+				{"daily-" + stamp(1) + ".json", 1},
+				{"daily-" + stamp(1) + ".md", 1},
+				{"daily-" + stamp(2) + ".json", 2},
+				{"daily-" + stamp(2) + ".md", 2},
+			},
+			remaining: []string{"daily-" + stamp(1) + ".json", "daily-" + stamp(1) + ".md"},
+		},
+		{
+			name: "deletes the csv files of a report together",
+			job:  ScheduleJob{Name: "daily", Format: FormatCSV, Retention: Retention{MaxReports: 1}},
+			files: []file{
+				{"daily-" + stamp(1) + CSVNodesSuffix, 1},
+				{"daily-" + stamp(1) + CSVAppsSuffix, 1},
+				{"daily-" + stamp(2) + CSVNodesSuffix, 2},
+				{"daily-" + stamp(2) + CSVAppsSuffix, 2},
+				{"daily.last.json", 1},
+			},
+			remaining: []string{"daily-" + stamp(1) + CSVAppsSuffix, "daily-" + stamp(1) + CSVNodesSuffix, "daily.last.json"},
+		},
+		{
+			name: "keeps the sqlite database and the copy of the last report",
+			job:  ScheduleJob{Name: "daily", Format: FormatSQLite, Retention: Retention{MaxReports: 1, MaxAge: "1h"}},
+			files: []file{
+				{"daily.db", 5},
+				{"daily.last.json", 5},
+			},
+			remaining: []string{"daily.db", "daily.last.json"},
+		},
+		{
+			name: "leaves the reports of a job whose name starts with the name of the job",
+			job:  ScheduleJob{Name: "daily", Retention: Retention{MaxReports: 1}},
+			files: []file{
+				{"daily-" + stamp(1) + ".json", 1},
+				{"daily-" + stamp(2) + ".json", 2},
+				{"daily-eu-" + stamp(3) + ".json", 3},
+				{"daily-eu-" + stamp(4) + ".json", 4},
+				{"notes.txt", 10},
+			},
+			remaining: []string{"daily-" + stamp(1) + ".json", "daily-eu-" + stamp(3) + ".json", "daily-eu-" + stamp(4) + ".json", "notes.txt"},
+		},
+		{
+			name: "no retention",
+			job:  ScheduleJob{Name: "daily"},
+			files: []file{
+				{"daily-" + stamp(1) + ".json", 1},
+				{"daily-" + stamp(20) + ".json", 20},
+			},
+			remaining: []string{"daily-" + stamp(1) + ".json", "daily-" + stamp(20) + ".json"},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			tt.job.ResultsDir = dir
+			for _, f := range tt.files {
+				file := filepath.Join(dir, f.name)
+				if err := ioutil.WriteFile(file, []byte("{}"), 0644); err != nil {
+					t.Fatal(err)
+				}
+				modTime := now.AddDate(0, 0, -f.daysAgo)
+				if err := os.Chtimes(file, modTime, modTime); err != nil {
+					t.Fatal(err)
+				}
+			}
+			applyRetention(tt.job, now)
+			infos, err := ioutil.ReadDir(dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			remaining := make([]string, 0, len(infos))
+			for _, info := range infos {
+				remaining = append(remaining, info.Name())
+			}
+			expected := append([]string(nil), tt.remaining...)
+			sort.Strings(expected)
+			if !reflect.DeepEqual(remaining, expected) {
+				t.Fatalf("expected the files %v, got %v", expected, remaining)
+			}
+		})
+	}
+}
+
+func TestPreviousPeriod(t *testing.T) {
+	date := func(year int, month time.Month, day, hour int) time.Time {
+		return time.Date(year, month, day, hour, 0, 0, 0, time.UTC)
+	}
+	// a Wednesday
+	now := time.Date(2026, 3, 4, 10, 30, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		period   string
+		now      time.Time
+		expected selector.Period
+	}{
+		{"hour", PeriodHour, now, selector.Period{Start: date(2026, 3, 4, 9), End: date(2026, 3, 4, 10)}},
+		{"hour on the hour", PeriodHour, date(2026, 3, 4, 10), selector.Period{Start: date(2026, 3, 4, 9), End: date(2026, 3, 4, 10)}},
+		{"day", PeriodDay, now, selector.Period{Start: date(2026, 3, 3, 0), End: date(2026, 3, 4, 0)}},
+		{"day by default", "", now, selector.Period{Start: date(2026, 3, 3, 0), End: date(2026, 3, 4, 0)}},
+		{"week", PeriodWeek, now, selector.Period{Start: date(2026, 2, 23, 0), End: date(2026, 3, 2, 0)}},
+		{"week on a Monday", PeriodWeek, date(2026, 3, 2, 0), selector.Period{Start: date(2026, 2, 23, 0), End: date(2026, 3, 2, 0)}},
+		{"week on a Sunday", PeriodWeek, date(2026, 3, 8, 23), selector.Period{Start: date(2026, 2, 23, 0), End: date(2026, 3, 2, 0)}},
+		{"month", PeriodMonth, now, selector.Period{Start: date(2026, 2, 1, 0), End: date(2026, 3, 1, 0)}},
+		{"month in January", PeriodMonth, date(2026, 1, 15, 0), selector.Period{Start: date(2025, 12, 1, 0), End: date(2026, 1, 1, 0)}},
+		{
+			name:     "day of a time in another zone",
+			period:   PeriodDay,
+			now:      time.Date(2026, 3, 4, 1, 30, 0, 0, time.FixedZone("UTC+3", 3*60*60)),
+			expected: selector.Period{Start: date(2026, 3, 2, 0), End: date(2026, 3, 3, 0)},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			got := previousPeriod(tt.period, tt.now)
+			if !got.Start.Equal(tt.expected.Start) || !got.End.Equal(tt.expected.End) {
+				t.Fatalf("expected %s to %s, got %s to %s", tt.expected.Start, tt.expected.End, got.Start, got.End)
+			}
+		})
+	}
+}
+
+func TestNextJob(t *testing.T) {
+	at := time.Date(2026, 3, 4, 10, 0, 0, 0, time.UTC)
+	jobs := []ScheduleJob{{Name: "daily"}, {Name: "hourly"}, {Name: "weekly"}}
+	tests := []struct {
+		name     string
+		next     map[string]time.Time
+		expected string
+		at       time.Time
+	}{
+		{
+			name:     "the job due first",
+			next:     map[string]time.Time{"daily": at.Add(2 * time.Hour), "hourly": at.Add(time.Hour), "weekly": at.Add(3 * time.Hour)},
+			expected: "hourly",
+			at:       at.Add(time.Hour),
+		},
+		{
+			name:     "the earlier job of the config on a tie",
+			next:     map[string]time.Time{"daily": at.Add(2 * time.Hour), "hourly": at, "weekly": at},
+			expected: "hourly",
+			at:       at,
+		},
+		{
+			name:     "the first job",
+			next:     map[string]time.Time{"daily": at, "hourly": at, "weekly": at},
+			expected: "daily",
+			at:       at,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			s := &scheduler{config: Config{Schedule: &ScheduleConfig{Jobs: jobs}}, status: make(map[string]JobStatus)}
+			for name, next := range tt.next {
+				s.setNext(name, next)
+			}
+			job, got := s.nextJob()
+			if job.Name != tt.expected || !got.Equal(tt.at) {
+				t.Fatalf("expected %s at %s, got %s at %s", tt.expected, tt.at, job.Name, got)
+			}
+		})
+	}
+}
+
+// The csv and sqlite jobs compare their next run to the json copy of their last report
+func TestPreviousReportFile(t *testing.T) {
+	dir := t.TempDir()
+	stamp := time.Date(2026, 3, 4, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		format   string
+		expected string
+	}{
+		{FormatJSON, "daily-2026-03-04T00-00.json"},
+		{FormatMarkdown, "daily-2026-03-04T00-00.json"},
+		{FormatCSV, "daily.last.json"},
+		{FormatSQLite, "daily.last.json"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.format, func(t *testing.T) {
+			job := ScheduleJob{Name: "daily", Format: tt.format, ResultsDir: dir}
+			status := JobStatus{LastFile: job.resultFile(stamp)}
+			if got := job.previousReportFile(status); got != filepath.Join(dir, tt.expected) {
+				t.Fatalf("expected %s, got %s", filepath.Join(dir, tt.expected), got)
+			}
+		})
+	}
+}
+
+func TestPreviousReportOfCSVJob(t *testing.T) {
+	job := ScheduleJob{Name: "daily", Format: FormatCSV, ResultsDir: t.TempDir()}
+	s := &scheduler{}
+	status := JobStatus{}
+	if previous := s.previousReport(job, status); previous != nil {
+		t.Fatalf("expected no previous report on the first run, got %+v", previous)
+	}
+	last := report.New("timeline", report.BlockReport{MinHeight: 10, MaxHeight: 20})
+	last.TotalRelaysCompleted = 42
+	status.LastFile = job.resultFile(time.Date(2026, 3, 4, 0, 0, 0, 0, time.UTC))
+	writeResultFile(last, job.previousReportFile(status))
+	previous := s.previousReport(job, status)
+	if previous == nil {
+		t.Fatal("expected the copy of the last report")
+	}
+	if previous.TotalRelaysCompleted != 42 || previous.BlockReport != last.BlockReport {
+		t.Fatalf("expected the last report %+v, got %+v", last, previous)
+	}
+}
@@ -0,0 +1,54 @@
+package main
+
+//go:generate go run . schema -output schema/report.schema.json
+
+import (
+	"bytes"
+	"flag"
+	"io/ioutil"
+	"os"
+
+	"github.com/pokt-network/relay_counter/logging"
+	"github.com/pokt-network/relay_counter/report"
+)
+
+const (
+	CommandSchema = "schema"
+)
+
+// Writes the JSON schema of the report, or checks the shipped schema is up to date
+func runSchema(args []string) {
+	fs := flag.NewFlagSet(os.Args[0]+" "+CommandSchema, flag.ExitOnError)
+	output := fs.String("output", "", "file the schema is written to, defaults to the screen")
+	check := fs.Bool("check", false, "exit with an error if the schema file differs from the generated schema")
+	lf := newLogFlags(fs)
+	_ = fs.Parse(args)
+	lf.apply()
+
+	bz, err := report.MarshalSchema()
+	if err != nil {
+		fatal(err)
+	}
+	if *check {
+		file := *output
+		if file == "" {
+			file = report.SchemaFile
+		}
+		existing, err := ioutil.ReadFile(file)
+		if err != nil {
+			fatal(err)
+		}
+		if !bytes.Equal(existing, bz) {
+			fatal(NewOutdatedSchemaError(file))
+		}
+		logging.Info("The schema is up to date", "file", file)
+		return
+	}
+	if *output == "" {
+		_, _ = os.Stdout.Write(bz)
+		return
+	}
+	if err := ioutil.WriteFile(*output, bz, 0644); err != nil {
+		fatal(err)
+	}
+}
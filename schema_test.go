@@ -0,0 +1,61 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/pokt-network/relay_counter/report"
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// The shipped schema must be the one generated from the report types, run go generate ./... after changing them
+func TestSchemaUpToDate(t *testing.T) {
+	generated, err := report.MarshalSchema()
+	if err != nil {
+		t.Fatal(err)
+	}
+	shipped, err := ioutil.ReadFile(report.SchemaFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(shipped, generated) {
+		t.Fatalf("%s is out of date: %s", report.SchemaFile, firstDiff(shipped, generated))
+	}
+}
+
+// Every golden report of the end to end scenarios must be valid against the shipped schema
+func TestGoldenReportsMatchSchema(t *testing.T) {
+	schemaFile, err := filepath.Abs(report.SchemaFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	schema, err := gojsonschema.NewSchema(gojsonschema.NewReferenceLoader("file://" + filepath.ToSlash(schemaFile)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	goldens, err := filepath.Glob(filepath.Join(e2eDir, "golden", "*.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(goldens) == 0 {
+		t.Fatalf("no golden report under %s", e2eDir)
+	}
+	for _, golden := range goldens {
+		golden := golden
+		t.Run(filepath.Base(golden), func(t *testing.T) {
+			bz, err := ioutil.ReadFile(golden)
+			if err != nil {
+				t.Fatal(err)
+			}
+			result, err := schema.Validate(gojsonschema.NewBytesLoader(bz))
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, e := range result.Errors() {
+				t.Error(e.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package selector
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	InvalidUnitError = "ERROR: unrecognized unit: "
+)
+
+func NewInvalidStartEndError(s, e int64, unit string) error {
+	return fmt.Errorf("ERROR: unable to interpret timeline: (Start) %d%s Ago to (End) %d%s Ago is not a valid range. Start must come before End", s, unit, e, unit)
+}
+
+func NewInvalidUnitError(unit string) error {
+	return fmt.Errorf("ERROR: %s%s, valid units: (minutes, hours, days, weeks, blocks, sessions)", InvalidUnitError, unit)
+}
+
+func NewInvalidMinimumHeightError(minHeight int64) error {
+	return fmt.Errorf("ERROR: the start height is less than 0 (%d), ensure your pocket client is synced and the start and end values are within bounds", minHeight)
+}
+
+func NewInvalidSelectorError(selector string) error {
+	return fmt.Errorf("ERROR: unrecognized selector: %s, selector must be one of following: timeline | byBlock", selector)
+}
+
+func NewInvalidByBlockStartError(start int64) error {
+	return fmt.Errorf("ERROR: byBlock.start must be at least 1, got %d", start)
+}
+
+func NewInvalidByBlockRangeError(start, end int64) error {
+	return fmt.Errorf("ERROR: byBlock.start (%d) must be less than byBlock.end (%d), the range ends right before byBlock.end", start, end)
+}
+
+func NewInvalidBlocksPerSessionError(blocksPerSession int64) error {
+	return fmt.Errorf("ERROR: params.blocks_per_session must be greater than 0 when the timeline unit is sessions, got %d", blocksPerSession)
+}
+
+func NewInvalidPeriodError(start, end time.Time) error {
+	return fmt.Errorf("ERROR: the period start (%s) must be before its end (%s)", start.Format(time.RFC3339), end.Format(time.RFC3339))
+}
+
+func NewIncompletePeriodError(end time.Time, latestHeight int64, latestTime time.Time) error {
+	return fmt.Errorf("ERROR: the period ending at %s is not complete, the latest block %d is at %s, ensure your pocket client is synced", end.Format(time.RFC3339), latestHeight, latestTime.Format(time.RFC3339))
+}
@@ -0,0 +1,409 @@
+// Package selector resolves the block range of a report from a timeline relative to the latest block, from a period of
+// absolute times or from explicit heights.
+package selector
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"math/bits"
+	"strings"
+	"time"
+
+	"github.com/pokt-network/relay_counter/client"
+	"github.com/pokt-network/relay_counter/logging"
+	"github.com/pokt-network/relay_counter/progress"
+	"github.com/pokt-network/relay_counter/report"
+	coretypes "github.com/tendermint/tendermint/rpc/core/types"
+)
+
+const (
+	SelectorTimeline = "timeline"
+	SelectorByBlock  = "byBlock"
+	SelectorPeriod   = "period"
+	UnitBlocks       = "blocks"
+	UnitBlock        = "block"
+	UnitB            = "b"
+	UnitSessions     = "sessions"
+	UnitSession      = "session"
+	UnitS            = "s"
+	UnitMinutes      = "minutes"
+	UnitMinute       = "minute"
+	UnitMin          = "min"
+	UnitM            = "m"
+	UnitHours        = "hours"
+	UnitHour         = "hour"
+	UnitHr           = "hr"
+	UnitH            = "h"
+	UnitDays         = "days"
+	UnitDay          = "day"
+	UnitD            = "d"
+	UnitWeeks        = "weeks"
+	UnitWeek         = "week"
+	UnitW            = "w"
+)
+
+type Timeline struct {
+	Start int64  `json:"start"`
+	End   int64  `json:"end"`
+	Unit  string `json:"unit"`
+}
+
+type TimelineJSON Timeline
+
+type ByBlock struct {
+	Start int64 `json:"start"`
+	End   int64 `json:"end"`
+}
+
+// Absolute times, the range goes from the block closest to Start up to the block closest to End
+type Period struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+type Params struct {
+	AppxBlockTimeInMinutes int64 `json:"approx_block_time_in_min"`
+	BlocksPerSession       int64 `json:"blocks_per_session"`
+}
+
+// How the block range is selected, Selector is SelectorTimeline, SelectorByBlock or SelectorPeriod, which read Timeline,
+// ByBlock or Period respectively
+type Options struct {
+	Selector string
+	Timeline Timeline
+	ByBlock  ByBlock
+	Period   Period
+	Params   Params
+}
+
+// Start and end are read as values ago, either sign is accepted. The timeline is checked by Validate
+func (t *Timeline) UnmarshalJSON(data []byte) error {
+	tlj := TimelineJSON{}
+	err := json.Unmarshal(data, &tlj)
+	if err != nil {
+		return err
+	}
+	*t = Timeline(tlj).Normalized()
+	return nil
+}
+
+// Returns the timeline with start and end counted back from the latest block, as negative values
+func (t Timeline) Normalized() Timeline {
+	t.End = int64(math.Abs(float64(t.End)) * -1)
+	t.Start = int64(math.Abs(float64(t.Start)) * -1)
+	return t
+}
+
+// Checks the unit and that the start comes before the end, on a normalized timeline
+func (t Timeline) Validate() (errs []error) {
+	if t.Start > t.End {
+		errs = append(errs, NewInvalidStartEndError(t.Start*-1, t.End*-1, t.Unit))
+	}
+	switch strings.ToLower(t.Unit) {
+	case UnitMinutes, UnitMinute, UnitMin, UnitM:
+		// all good
+	case UnitHours, UnitHour, UnitHr, UnitH:
+		// all good
+	case UnitDays, UnitDay, UnitD:
+		// all good
+	case UnitWeeks, UnitWeek, UnitW:
+		// all good
+	case UnitBlocks, UnitBlock, UnitB:
+		// all good
+	case UnitSessions, UnitSession, UnitS:
+		// all good
+	default:
+		errs = append(errs, NewInvalidUnitError(t.Unit))
+	}
+	return errs
+}
+
+// The plural name of the unit, e.g. hours for h, hr and hour, the unit itself when it is unknown
+func CanonicalUnit(unit string) string {
+	switch strings.ToLower(unit) {
+	case UnitMinutes, UnitMinute, UnitMin, UnitM:
+		return UnitMinutes
+	case UnitHours, UnitHour, UnitHr, UnitH:
+		return UnitHours
+	case UnitDays, UnitDay, UnitD:
+		return UnitDays
+	case UnitWeeks, UnitWeek, UnitW:
+		return UnitWeeks
+	case UnitBlocks, UnitBlock, UnitB:
+		return UnitBlocks
+	case UnitSessions, UnitSession, UnitS:
+		return UnitSessions
+	}
+	return unit
+}
+
+// Checks the range starts at a positive height before its end
+func (b ByBlock) Validate() (errs []error) {
+	if b.Start < 1 {
+		errs = append(errs, NewInvalidByBlockStartError(b.Start))
+	}
+	if b.Start >= b.End {
+		errs = append(errs, NewInvalidByBlockRangeError(b.Start, b.End))
+	}
+	return errs
+}
+
+// Checks the selector and the fields it uses, every problem found is returned
+func (o Options) Validate() (errs []error) {
+	switch o.Selector {
+	case SelectorTimeline:
+		errs = append(errs, o.Timeline.Validate()...)
+		switch strings.ToLower(o.Timeline.Unit) {
+		case UnitSessions, UnitSession, UnitS:
+			if o.Params.BlocksPerSession <= 0 {
+				errs = append(errs, NewInvalidBlocksPerSessionError(o.Params.BlocksPerSession))
+			}
+		}
+	case SelectorByBlock:
+		errs = append(errs, o.ByBlock.Validate()...)
+	case SelectorPeriod:
+		if !o.Period.Start.Before(o.Period.End) {
+			errs = append(errs, NewInvalidPeriodError(o.Period.Start, o.Period.End))
+		}
+	default:
+		errs = append(errs, NewInvalidSelectorError(o.Selector))
+	}
+	return errs
+}
+
+// Resolves the block range of the report using the selector of the options
+func Select(ctx context.Context, c *client.Client, opts Options) (blockReport report.BlockReport, err error) {
+	if errs := opts.Validate(); len(errs) != 0 {
+		return blockReport, errs[0]
+	}
+	switch opts.Selector {
+	case SelectorTimeline:
+		logging.Info("Converting the timeline to block heights", "start", opts.Timeline.Start, "end", opts.Timeline.End, "unit", opts.Timeline.Unit)
+		return ConvertTimelineToHeights(ctx, c, opts.Timeline, opts.Params)
+	case SelectorByBlock:
+		logging.Info("Using byBlock as block selector", "start", opts.ByBlock.Start, "end", opts.ByBlock.End)
+		blockReport.MinHeight = opts.ByBlock.Start
+		blockReport.MaxHeight = opts.ByBlock.End
+		return blockReport, nil
+	case SelectorPeriod:
+		logging.Info("Converting the period to block heights", "start", opts.Period.Start.String(), "end", opts.Period.End.String())
+		latestHeight, latestTime, err := getLatestBlock(ctx, c)
+		if err != nil {
+			return blockReport, err
+		}
+		return PeriodHeightsAt(ctx, c, opts.Period, latestHeight, latestTime)
+	default:
+		return blockReport, NewInvalidSelectorError(opts.Selector)
+	}
+}
+
+// Resolves the block ranges of every options against the same latest block, so the timelines of a batch line up
+func SelectAll(ctx context.Context, c *client.Client, opts []Options) (blockReports []report.BlockReport, err error) {
+	var latestHeight int64
+	var latestTime time.Time
+	for _, o := range opts {
+		if errs := o.Validate(); len(errs) != 0 {
+			return nil, errs[0]
+		}
+		if o.Selector != SelectorByBlock && latestHeight == 0 {
+			if latestHeight, latestTime, err = getLatestBlock(ctx, c); err != nil {
+				return nil, err
+			}
+		}
+	}
+	blockReports = make([]report.BlockReport, 0, len(opts))
+	for _, o := range opts {
+		blockReport := report.BlockReport{MinHeight: o.ByBlock.Start, MaxHeight: o.ByBlock.End}
+		switch o.Selector {
+		case SelectorTimeline:
+			blockReport, err = TimelineHeightsAt(ctx, c, o.Timeline, o.Params, latestHeight, latestTime)
+		case SelectorPeriod:
+			blockReport, err = PeriodHeightsAt(ctx, c, o.Period, latestHeight, latestTime)
+		}
+		if err != nil {
+			return nil, err
+		}
+		blockReports = append(blockReports, blockReport)
+	}
+	return blockReports, nil
+}
+
+// Splits the range of the options into consecutive ranges of size units of the timeline, or of size blocks for byBlock.
+// The last range is shorter when size does not divide the range
+func (o Options) Split(size int64) (split []Options) {
+	switch o.Selector {
+	case SelectorTimeline:
+		for start := o.Timeline.Start; start < o.Timeline.End; start += size {
+			part := o
+			part.Timeline.Start, part.Timeline.End = start, start+size
+			if part.Timeline.End > o.Timeline.End {
+				part.Timeline.End = o.Timeline.End
+			}
+			split = append(split, part)
+		}
+	case SelectorByBlock:
+		for start := o.ByBlock.Start; start < o.ByBlock.End; start += size {
+			part := o
+			part.ByBlock.Start, part.ByBlock.End = start, start+size
+			if part.ByBlock.End > o.ByBlock.End {
+				part.ByBlock.End = o.ByBlock.End
+			}
+			split = append(split, part)
+		}
+	}
+	return split
+}
+
+func getLatestBlock(ctx context.Context, c *client.Client) (latestHeight int64, latestTime time.Time, err error) {
+	latestheight, err := c.GetLatestHeight(ctx)
+	if err != nil {
+		return 0, latestTime, err
+	}
+	block, err := c.GetBlock(ctx, latestheight)
+	if err != nil {
+		return 0, latestTime, err
+	}
+	logging.Info("Got the latest block", "height", block.Block.Height, "time", block.Block.Time.String())
+	return block.Block.Height, block.Block.Time, nil
+}
+
+func ConvertTimelineToHeights(ctx context.Context, c *client.Client, timeline Timeline, params Params) (blockReport report.BlockReport, err error) {
+	latestHeight, latestTime, err := getLatestBlock(ctx, c)
+	if err != nil {
+		return blockReport, err
+	}
+	return TimelineHeightsAt(ctx, c, timeline, params, latestHeight, latestTime)
+}
+
+// Resolves the timeline counting back from the given latest block
+func TimelineHeightsAt(ctx context.Context, c *client.Client, timeline Timeline, params Params, latestHeight int64, latestTime time.Time) (blockReport report.BlockReport, err error) {
+	// start and end are negative values
+	var startInBlocks, endInBlocks, minHeight, maxHeight int64
+	var targetStartTime, targetEndTime time.Time
+	switch strings.ToLower(timeline.Unit) {
+	case UnitMinutes, UnitMinute, UnitMin, UnitM:
+		targetStartTime, targetEndTime = GetTargetTimes(timeline, latestTime, time.Minute)
+		minHeight, maxHeight, err = GetClosestHeights(ctx, c, latestHeight, targetStartTime, latestTime, targetEndTime)
+	case UnitHours, UnitHour, UnitHr, UnitH:
+		targetStartTime, targetEndTime = GetTargetTimes(timeline, latestTime, time.Hour)
+		minHeight, maxHeight, err = GetClosestHeights(ctx, c, latestHeight, targetStartTime, latestTime, targetEndTime)
+	case UnitDays, UnitDay, UnitD:
+		targetStartTime, targetEndTime = GetTargetTimes(timeline, latestTime, time.Hour*24)
+		minHeight, maxHeight, err = GetClosestHeights(ctx, c, latestHeight, targetStartTime, latestTime, targetEndTime)
+	case UnitWeeks, UnitWeek, UnitW:
+		targetStartTime, targetEndTime = GetTargetTimes(timeline, latestTime, time.Hour*24*7)
+		minHeight, maxHeight, err = GetClosestHeights(ctx, c, latestHeight, targetStartTime, latestTime, targetEndTime)
+	case UnitBlocks, UnitBlock, UnitB:
+		minHeight = latestHeight + timeline.Start
+		maxHeight = latestHeight + timeline.End
+	case UnitSessions, UnitSession, UnitS:
+		startInBlocks = timeline.Start * params.BlocksPerSession
+		endInBlocks = timeline.End * params.BlocksPerSession
+		minHeight = latestHeight + startInBlocks
+		maxHeight = latestHeight + endInBlocks
+	default:
+		return blockReport, NewInvalidUnitError(timeline.Unit)
+	}
+	if err != nil {
+		return blockReport, err
+	}
+	if minHeight < 0 {
+		err = NewInvalidMinimumHeightError(minHeight)
+		return
+	}
+	logging.Info("Selected the block range", "min_height", minHeight, "max_height", maxHeight)
+	blockReport = report.BlockReport{
+		MinHeight: minHeight,
+		MaxHeight: maxHeight,
+	}
+	return
+}
+
+// Resolves the period given the latest block, which must not be before the end of the period
+func PeriodHeightsAt(ctx context.Context, c *client.Client, period Period, latestHeight int64, latestTime time.Time) (blockReport report.BlockReport, err error) {
+	if latestTime.Before(period.End) {
+		return blockReport, NewIncompletePeriodError(period.End, latestHeight, latestTime)
+	}
+	minHeight, maxHeight, err := GetClosestHeights(ctx, c, latestHeight, period.Start, latestTime, period.End)
+	if err != nil {
+		return blockReport, err
+	}
+	logging.Info("Selected the block range", "min_height", minHeight, "max_height", maxHeight)
+	return report.BlockReport{MinHeight: minHeight, MaxHeight: maxHeight}, nil
+}
+
+func GetTargetTimes(timeline Timeline, latestTime time.Time, unit time.Duration) (targetStartTime, targetEndTime time.Time) {
+	st := time.Duration(timeline.Start) * unit
+	et := time.Duration(timeline.End) * unit
+	targetStartTime = latestTime.Add(st)
+	targetEndTime = latestTime.Add(et)
+	logging.Debug("Target times", "start", targetStartTime.String(), "end", targetEndTime.String())
+	return
+}
+
+func GetClosestHeights(ctx context.Context, c *client.Client, latestHeight int64, targetStartTime, latestBlockTime, targetEndTime time.Time) (startHeight, endHeight int64, err error) {
+	appxStartHeight := latestHeight - int64(latestBlockTime.Sub(targetStartTime).Minutes()/15)
+	appxEndHeight := latestHeight - int64(latestBlockTime.Sub(targetEndTime).Minutes()/15)
+	// each search takes about log2(latestHeight) blocks
+	p := progress.Start(progress.PhaseHeights, 2*int64(bits.Len64(uint64(latestHeight))))
+	defer p.Done()
+	startHeight, err = BlockBinarySearch(ctx, c, p, targetStartTime, latestHeight, appxStartHeight)
+	if err != nil {
+		return
+	}
+	logging.Debug("Closest start height found", "height", startHeight)
+	endHeight, err = BlockBinarySearch(ctx, c, p, targetEndTime, latestHeight, appxEndHeight)
+	if err != nil {
+		return
+	}
+	logging.Debug("Closest end height found", "height", endHeight)
+	return
+}
+
+// Searches the height whose block time is the closest to the target, every block retrieved is a step of p (which may be nil)
+func BlockBinarySearch(ctx context.Context, c *client.Client, p *progress.Phase, targetStartTime time.Time, latestHeight, tryHeight int64) (closestHeight int64, err error) {
+	logging.Debug("Performing a binary search for the closest height to the target time", "target", targetStartTime.String())
+	max := latestHeight
+	closestHeight = tryHeight
+	closestTime := time.Time{}
+	for min := int64(0); min < max && max-min != 1; {
+		logging.Debug("Binary search step", "min", min, "max", max, "try_height", tryHeight, "closest_height", closestHeight)
+		// get the latest height
+		var block *coretypes.ResultBlock
+		err = c.Retry(ctx, "block by height", 5*time.Second, p.Attempts(func() (err error) {
+			block, err = c.GetBlock(ctx, tryHeight)
+			return
+		}))
+		if err != nil {
+			return closestHeight, report.NewHeightError(tryHeight, report.PhaseBlock, err)
+		}
+		p.Add(1)
+		// if tryHeight block is before our target...
+		if block.Block.Time.Before(targetStartTime) {
+			// minimum is where the pivot was
+			min = tryHeight
+		} else {
+			// maximum is where the pivot was
+			max = tryHeight
+		}
+		// see if target height is closer than the current closest height
+		if IsCloserThan(block.Block.Time, closestTime, targetStartTime) {
+			// if is closer, let's update the closest
+			closestTime = block.Block.Time
+			closestHeight = block.Block.Height
+		}
+		// new pivot
+		tryHeight = (min + max) / 2
+	}
+	return
+}
+
+func IsCloserThan(check, other, target time.Time) bool {
+	diff1 := math.Abs(float64(target.Sub(check).Nanoseconds()))
+	diff2 := math.Abs(float64(target.Sub(other).Nanoseconds()))
+	if diff1 >= diff2 {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,67 @@
+package selector
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplit(t *testing.T) {
+	byBlock := func(start, end int64) Options {
+		return Options{Selector: SelectorByBlock, ByBlock: ByBlock{Start: start, End: end}}
+	}
+	timeline := func(start, end int64) Options {
+		return Options{Selector: SelectorTimeline, Timeline: Timeline{Start: start, End: end, Unit: UnitHours}}
+	}
+	tests := []struct {
+		name     string
+		opts     Options
+		size     int64
+		expected []Options
+	}{
+		{
+			name:     "heights divided evenly",
+			opts:     byBlock(10, 40),
+			size:     10,
+			expected: []Options{byBlock(10, 20), byBlock(20, 30), byBlock(30, 40)},
+		},
+		{
+			name:     "a shorter last range",
+			opts:     byBlock(10, 35),
+			size:     10,
+			expected: []Options{byBlock(10, 20), byBlock(20, 30), byBlock(30, 35)},
+		},
+		{
+			name:     "a size larger than the range",
+			opts:     byBlock(10, 15),
+			size:     10,
+			expected: []Options{byBlock(10, 15)},
+		},
+		{
+			name:     "a timeline",
+			opts:     timeline(-5, 0),
+			size:     2,
+			expected: []Options{timeline(-5, -3), timeline(-3, -1), timeline(-1, 0)},
+		},
+		{
+			name: "a period is not split",
+			opts: Options{Selector: SelectorPeriod},
+			size: 2,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.opts.Split(tt.size); !reflect.DeepEqual(got, tt.expected) {
+				t.Fatalf("expected %+v, got %+v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestCanonicalUnit(t *testing.T) {
+	for unit, expected := range map[string]string{"h": UnitHours, "Hr": UnitHours, "day": UnitDays, "s": UnitSessions, "b": UnitBlocks, "fortnights": "fortnights"} {
+		if got := CanonicalUnit(unit); got != expected {
+			t.Errorf("expected %s for %s, got %s", expected, unit, got)
+		}
+	}
+}
@@ -0,0 +1,241 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"net"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/pokt-network/relay_counter/client"
+	"github.com/pokt-network/relay_counter/indexer"
+	"github.com/pokt-network/relay_counter/logging"
+	"github.com/pokt-network/relay_counter/report"
+)
+
+const (
+	CommandServe   = "serve"
+	SelectorServe  = "serve"
+	ReportPath     = "/report"
+	HealthPath     = "/health"
+	MaxCachedItems = 256
+	// the heights of a request when -maxRange is not set, a week at 15 minutes per block
+	DefaultMaxRange = 672
+)
+
+// Serves reports computed on demand from the cached chain data
+type ReportServer struct {
+	client *client.Client
+	// the groups and the config recorded in the metadata, the range and filter come from the requests
+	opts     indexer.Options
+	maxRange int64
+	data     *indexer.ChainDataCache
+	mu       sync.Mutex
+	cache    map[string]report.Report
+}
+
+// A request is refused above maxRange heights, at most cacheHeights heights of chain data are kept in memory
+func NewReportServer(c *client.Client, opts indexer.Options, maxRange int64, cacheHeights int) *ReportServer {
+	return &ReportServer{
+		client:   c,
+		opts:     opts,
+		maxRange: maxRange,
+		data:     indexer.NewChainDataCache(c, cacheHeights),
+		cache:    make(map[string]report.Report),
+	}
+}
+
+func (s *ReportServer) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(ReportPath, s.handleReport)
+	mux.HandleFunc(HealthPath, s.handleHealth)
+	return mux
+}
+
+// GET /report?start=<height>&end=<height>[&node=<address>][&app=<address or public key>][&chain=<id>]
+func (s *ReportServer) handleReport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSONError(w, http.StatusMethodNotAllowed, NewMethodNotAllowedError(r.Method))
+		return
+	}
+	query := r.URL.Query()
+	start, err := strconv.ParseInt(query.Get("start"), 10, 64)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, NewInvalidQueryParamError("start", query.Get("start")))
+		return
+	}
+	end, err := strconv.ParseInt(query.Get("end"), 10, 64)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, NewInvalidQueryParamError("end", query.Get("end")))
+		return
+	}
+	if start < 1 || start >= end {
+		writeJSONError(w, http.StatusBadRequest, NewInvalidBlockRangeError(start, end))
+		return
+	}
+	if s.maxRange > 0 && end-start > s.maxRange {
+		writeJSONError(w, http.StatusBadRequest, NewRangeTooLargeError(start, end, s.maxRange))
+		return
+	}
+	filters := report.Filters{
+		Nodes:  queryList(query["node"]),
+		Apps:   queryList(query["app"]),
+		Chains: queryList(query["chain"]),
+	}
+	key := reportCacheKey(start, end, filters)
+	s.mu.Lock()
+	result, found := s.cache[key]
+	s.mu.Unlock()
+	if !found {
+		latestHeight, err := s.client.GetLatestHeight(r.Context())
+		if err != nil {
+			writeJSONError(w, http.StatusBadGateway, err)
+			return
+		}
+		if end > latestHeight {
+			writeJSONError(w, http.StatusBadRequest, NewHeightNotAvailableError(end, latestHeight))
+			return
+		}
+		filter, err := report.NewFilter(filters)
+		if err != nil {
+			writeJSONError(w, http.StatusBadRequest, err)
+			return
+		}
+		logging.Info("Computing the report", "key", key)
+		blockTxsMap, claimsMap, supplyStart, supplyEnd, err := s.data.Get(r.Context(), start, end)
+		var incompleteErr *report.IncompleteError
+		if err != nil && !errors.As(err, &incompleteErr) {
+			writeJSONError(w, http.StatusBadGateway, err)
+			return
+		}
+		blockReport := report.BlockReport{MinHeight: start, MaxHeight: end}
+		result = indexer.ProcessChainData(
+			indexer.ChainData{BlockTxs: blockTxsMap, Claims: claimsMap, SupplyStart: supplyStart, SupplyEnd: supplyEnd},
+			indexer.ProcessOptions{Selector: SelectorServe, BlockReport: blockReport, Filter: filter, Groups: s.opts.Groups},
+		)
+		if incompleteErr != nil {
+			report.AddFailedHeights(&result, incompleteErr.FailedHeights)
+		}
+		metadata, metadataFailed := indexer.NewRunMetadata(r.Context(), s.client, blockReport, s.opts)
+		result.Metadata = &metadata
+		report.AddFailedHeights(&result, metadataFailed)
+		// an incomplete report is not cached, so the next request retries the heights that failed
+		if !result.Incomplete {
+			s.mu.Lock()
+			if len(s.cache) >= MaxCachedItems {
+				s.cache = make(map[string]report.Report)
+			}
+			s.cache[key] = result
+			s.mu.Unlock()
+		}
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (s *ReportServer) handleHealth(w http.ResponseWriter, r *http.Request) {
+	latestHeight, err := s.client.GetLatestHeight(r.Context())
+	if err != nil {
+		writeJSON(w, http.StatusServiceUnavailable, map[string]interface{}{"status": "unavailable", "error": err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"status": "ok", "latest_height": latestHeight})
+}
+
+// Identical ranges and filters share the same key regardless of the order of the values
+func reportCacheKey(start, end int64, f report.Filters) string {
+	normalize := func(values []string) string {
+		v := append([]string(nil), values...)
+		for i := range v {
+			v[i] = strings.ToLower(v[i])
+		}
+		sort.Strings(v)
+		return strings.Join(v, ",")
+	}
+	return strings.Join([]string{
+		"start=" + strconv.FormatInt(start, 10),
+		"end=" + strconv.FormatInt(end, 10),
+		"node=" + normalize(f.Nodes),
+		"app=" + normalize(f.Apps),
+		"chain=" + normalize(f.Chains),
+	}, "&")
+}
+
+// Accepts both repeated and comma separated query values
+func queryList(values []string) (list []string) {
+	for _, v := range values {
+		list = append(list, splitList(v)...)
+	}
+	return
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		logging.Error("Could not write the response", "err", err.Error())
+	}
+}
+
+func writeJSONError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+// Serves reports over HTTP until the process is stopped
+func runServe(args []string) {
+	fs := flag.NewFlagSet(os.Args[0]+" "+CommandServe, flag.ExitOnError)
+	listen := fs.String("listen", ":8084", "address the API listens on")
+	maxRange := fs.Int64("maxRange", DefaultMaxRange, "most heights a report request can span, 0 for no limit")
+	cacheHeights := fs.Int("cacheHeights", indexer.DefaultCacheEntries, "most heights of chain data kept in memory")
+	cf := newConfigFlags(fs)
+	lf := newLogFlags(fs)
+	_ = fs.Parse(args)
+	lf.apply()
+
+	c, err := cf.load()
+	if err != nil {
+		fatal(err)
+	}
+
+	opts, err := c.IndexerOptions()
+	if err != nil {
+		fatal(err)
+	}
+
+	ctx := interruptContext()
+	pocket := c.Client()
+	logging.Info("Testing the pocket endpoint", "endpoint", pocket.Endpoint())
+	if err := pocket.Test(ctx); err != nil {
+		fatal(err)
+	}
+
+	s := NewReportServer(pocket, opts, *maxRange, *cacheHeights)
+	server := &http.Server{
+		Addr:    *listen,
+		Handler: s.Handler(),
+		// the requests retrieving chain data are cancelled on shutdown
+		BaseContext: func(net.Listener) context.Context { return ctx },
+	}
+	shutdown := make(chan struct{})
+	go func() {
+		defer close(shutdown)
+		<-ctx.Done()
+		logging.Info("Interrupted, shutting down the API")
+		shutdownCtx, cancel := indexer.FinishContext(ctx)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			logging.Error("Could not shut down the API", "err", err.Error())
+		}
+	}()
+	logging.Info("Serving reports", "address", *listen, "path", ReportPath)
+	if err := server.ListenAndServe(); err != http.ErrServerClosed {
+		fatal(err)
+	}
+	// wait for the requests in flight
+	<-shutdown
+}
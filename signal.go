@@ -0,0 +1,40 @@
+package main
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/pokt-network/relay_counter/logging"
+)
+
+// Returns a context cancelled on the first SIGINT or SIGTERM, so the run stops and writes what it retrieved.
+// A second signal exits right away
+func interruptContext() context.Context {
+	ctx, cancel := context.WithCancel(context.Background())
+	signals := make(chan os.Signal, 2)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		sig := <-signals
+		logging.Info("Stopping and writing what was retrieved, send the signal again to exit right away", "signal", sig.String())
+		cancel()
+		<-signals
+		logging.Info("Exiting without writing")
+		os.Exit(ExitCodeInterrupted)
+	}()
+	return ctx
+}
+
+// Sleeps for d, returns false if ctx was cancelled before
+func sleepContext(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
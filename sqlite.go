@@ -0,0 +1,145 @@
+package main
+
+import (
+	"database/sql"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/pokt-network/relay_counter/report"
+)
+
+const (
+	DefaultSQLiteFile = "result/relay_counter.db"
+	sqliteSchema      = `
+CREATE TABLE IF NOT EXISTS runs (
+	id                         INTEGER PRIMARY KEY AUTOINCREMENT,
+	created_at                 TIMESTAMP NOT NULL,
+	endpoint                   TEXT NOT NULL,
+	selector                   TEXT NOT NULL,
+	total_relays_completed     INTEGER NOT NULL,
+	total_challenges_completed INTEGER NOT NULL,
+	total_minted               INTEGER NOT NULL,
+	total_good_txs             INTEGER NOT NULL,
+	total_bad_txs              INTEGER NOT NULL,
+	proof_msgs                 INTEGER NOT NULL,
+	schema_version             TEXT,
+	tool_version               TEXT,
+	config_hash                TEXT,
+	profile                    TEXT,
+	incomplete                 INTEGER NOT NULL DEFAULT 0,
+	start_block_time           TIMESTAMP,
+	end_block_time             TIMESTAMP
+);
+CREATE TABLE IF NOT EXISTS ranges (
+	run_id     INTEGER NOT NULL REFERENCES runs(id),
+	min_height INTEGER NOT NULL,
+	max_height INTEGER NOT NULL
+);
+CREATE TABLE IF NOT EXISTS node_relays (
+	run_id  INTEGER NOT NULL REFERENCES runs(id),
+	address TEXT NOT NULL,
+	chain   TEXT NOT NULL,
+	relays  INTEGER NOT NULL
+);
+CREATE TABLE IF NOT EXISTS app_relays (
+	run_id  INTEGER NOT NULL REFERENCES runs(id),
+	address TEXT NOT NULL,
+	chain   TEXT NOT NULL,
+	relays  INTEGER NOT NULL
+);
+CREATE TABLE IF NOT EXISTS chain_relays (
+	run_id INTEGER NOT NULL REFERENCES runs(id),
+	chain  TEXT NOT NULL,
+	relays INTEGER NOT NULL
+);
+CREATE TABLE IF NOT EXISTS bad_txs (
+	run_id INTEGER NOT NULL REFERENCES runs(id),
+	code   INTEGER NOT NULL,
+	count  INTEGER NOT NULL
+);
+CREATE INDEX IF NOT EXISTS node_relays_address ON node_relays(address);
+CREATE INDEX IF NOT EXISTS app_relays_address ON app_relays(address);
+CREATE INDEX IF NOT EXISTS chain_relays_chain ON chain_relays(chain);
+`
+)
+
+// Appends the report as a new run to the sqlite database, creating the tables if needed
+func writeSQLiteFile(result report.Report, config Config, file string) (runID int64, err error) {
+	db, err := sql.Open("sqlite3", file)
+	if err != nil {
+		return 0, err
+	}
+	defer db.Close()
+	if _, err = db.Exec(sqliteSchema); err != nil {
+		return 0, err
+	}
+	var toolVersion, configHash, profile sql.NullString
+	var startBlockTime, endBlockTime sql.NullTime
+	if m := result.Metadata; m != nil {
+		toolVersion = sql.NullString{String: m.ToolVersion, Valid: m.ToolVersion != ""}
+		configHash = sql.NullString{String: m.ConfigHash, Valid: m.ConfigHash != ""}
+		profile = sql.NullString{String: m.Profile, Valid: m.Profile != ""}
+		if m.StartBlockTime != nil {
+			startBlockTime = sql.NullTime{Time: m.StartBlockTime.UTC(), Valid: true}
+		}
+		if m.EndBlockTime != nil {
+			endBlockTime = sql.NullTime{Time: m.EndBlockTime.UTC(), Valid: true}
+		}
+	}
+	tx, err := db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+	res, err := tx.Exec(`INSERT INTO runs (created_at, endpoint, selector, total_relays_completed, total_challenges_completed,
+		total_minted, total_good_txs, total_bad_txs, proof_msgs, schema_version, tool_version, config_hash, profile, incomplete,
+		start_block_time, end_block_time) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		time.Now().UTC(), config.Endpoint, result.BlockSelector, result.TotalRelaysCompleted, result.TotalChallengesCompleted,
+		result.TotalMinted, result.TotalGoodTxs, result.TotalBadTxs, result.TotalProofTxs, result.SchemaVersion, toolVersion,
+		configHash, profile, result.Incomplete, startBlockTime, endBlockTime)
+	if err != nil {
+		return 0, err
+	}
+	if runID, err = res.LastInsertId(); err != nil {
+		return 0, err
+	}
+	if _, err = tx.Exec(`INSERT INTO ranges (run_id, min_height, max_height) VALUES (?, ?, ?)`,
+		runID, result.BlockReport.MinHeight, result.BlockReport.MaxHeight); err != nil {
+		return 0, err
+	}
+	for address, nodeReport := range result.NodeReports {
+		for chain, relays := range nodeReport.ServiceReportByChain {
+			if _, err = tx.Exec(`INSERT INTO node_relays (run_id, address, chain, relays) VALUES (?, ?, ?, ?)`,
+				runID, address, chain, relays); err != nil {
+				return 0, err
+			}
+		}
+	}
+	for address, appReport := range result.AppReports {
+		for chain, relays := range appReport.ServicedReportByChain {
+			if _, err = tx.Exec(`INSERT INTO app_relays (run_id, address, chain, relays) VALUES (?, ?, ?, ?)`,
+				runID, address, chain, relays); err != nil {
+				return 0, err
+			}
+		}
+	}
+	chainRelays := report.RelaysByChain(result)
+	for _, chain := range sortedChains(chainRelays) {
+		if _, err = tx.Exec(`INSERT INTO chain_relays (run_id, chain, relays) VALUES (?, ?, ?)`,
+			runID, chain, chainRelays[chain]); err != nil {
+			return 0, err
+		}
+	}
+	for code, count := range result.BadTxsMap {
+		if _, err = tx.Exec(`INSERT INTO bad_txs (run_id, code, count) VALUES (?, ?, ?)`,
+			runID, code, count); err != nil {
+			return 0, err
+		}
+	}
+	err = tx.Commit()
+	return runID, err
+}
@@ -0,0 +1,286 @@
+package main
+
+import (
+	htmltemplate "html/template"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/pokt-network/relay_counter/report"
+)
+
+const (
+	SummaryTopN = 10
+)
+
+type RankedCount struct {
+	Name  string
+	Count int64
+	Share float64 // percent of the total
+	Width float64 // percent of the largest count, for the bar charts
+}
+
+// The values shown in the summary, derived from the report
+type Summary struct {
+	Report   report.Report
+	TopNodes []RankedCount
+	TopApps  []RankedCount
+	Chains   []RankedCount
+	Groups   []RankedCount
+	BadTxs   []RankedCount
+	TopN     int
+}
+
+func NewSummary(result report.Report, topN int) Summary {
+	nodes := make(map[string]int64, len(result.NodeReports))
+	for address, nodeReport := range result.NodeReports {
+		nodes[address] = nodeReport.TotalRelays
+	}
+	apps := make(map[string]int64, len(result.AppReports))
+	for address, appReport := range result.AppReports {
+		apps[address] = appReport.TotalRelays
+	}
+	groups := make(map[string]int64, len(result.GroupReports))
+	for name, groupReport := range result.GroupReports {
+		groups[name] = groupReport.TotalRelays
+	}
+	badTxs := make(map[string]int64, len(result.BadTxsMap))
+	for code, count := range result.BadTxsMap {
+		badTxs[strconv.FormatUint(uint64(code), 10)] = count
+	}
+	return Summary{
+		Report:   result,
+		TopNodes: rankCounts(nodes, topN),
+		TopApps:  rankCounts(apps, topN),
+		Chains:   rankCounts(report.RelaysByChain(result), 0),
+		Groups:   rankCounts(groups, 0),
+		BadTxs:   rankCounts(badTxs, 0),
+		TopN:     topN,
+	}
+}
+
+// Sorts the counts from largest to smallest, keeping the first n if n is greater than 0
+func rankCounts(counts map[string]int64, n int) []RankedCount {
+	var total, largest int64
+	ranked := make([]RankedCount, 0, len(counts))
+	for name, count := range counts {
+		total += count
+		if count > largest {
+			largest = count
+		}
+		ranked = append(ranked, RankedCount{Name: name, Count: count})
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].Count != ranked[j].Count {
+			return ranked[i].Count > ranked[j].Count
+		}
+		return ranked[i].Name < ranked[j].Name
+	})
+	if n > 0 && len(ranked) > n {
+		ranked = ranked[:n]
+	}
+	for i := range ranked {
+		if total != 0 {
+			ranked[i].Share = float64(ranked[i].Count) / float64(total) * 100
+		}
+		if largest != 0 {
+			ranked[i].Width = float64(ranked[i].Count) / float64(largest) * 100
+		}
+	}
+	return ranked
+}
+
+func formatSummaryTime(t *time.Time) string {
+	if t == nil {
+		return "unknown"
+	}
+	return t.Format("2006-01-02 15:04:05 MST")
+}
+
+var summaryFuncs = map[string]interface{}{
+	"time": formatSummaryTime,
+}
+
+const markdownSummaryTemplate = `# Relay report
+
+| Range | |
+|---|---|
+| Selector | {{.Report.BlockSelector}} |
+| Heights | {{.Report.BlockReport.MinHeight}} through {{.Report.BlockReport.MaxHeight}} (not included) |
+{{- with .Report.Metadata}}
+| Start block time | {{time .StartBlockTime}} |
+| End block time | {{time .EndBlockTime}} |
+| Generated at | {{.GeneratedAt.Format "2006-01-02 15:04:05 MST"}} by relay_counter {{.ToolVersion}} |
+{{- end}}
+
+## Totals
+
+| | |
+|---|---:|
+| Relays completed | {{.Report.TotalRelaysCompleted}} |
+| Challenges completed | {{.Report.TotalChallengesCompleted}} |
+| Minted | {{.Report.TotalMinted}} |
+| Good txs | {{.Report.TotalGoodTxs}} |
+| Bad txs | {{.Report.TotalBadTxs}} |
+| Proof txs | {{.Report.TotalProofTxs}} |
+{{- with .Report.Filtered}}
+
+## Filtered totals
+
+| | |
+|---|---:|
+| Relays completed | {{.TotalRelaysCompleted}} |
+| Challenges completed | {{.TotalChallengesCompleted}} |
+| Proof txs | {{.TotalProofTxs}} |
+{{- end}}
+
+## Chains
+
+| Chain | Relays | Share |
+|---|---:|---:|
+{{- range .Chains}}
+| {{.Name}} | {{.Count}} | {{printf "%.2f" .Share}}% |
+{{- end}}
+
+## Top {{.TopN}} nodes
+
+| Node | Relays | Share |
+|---|---:|---:|
+{{- range .TopNodes}}
+| {{.Name}} | {{.Count}} | {{printf "%.2f" .Share}}% |
+{{- end}}
+
+## Top {{.TopN}} apps
+
+| App | Relays | Share |
+|---|---:|---:|
+{{- range .TopApps}}
+| {{.Name}} | {{.Count}} | {{printf "%.2f" .Share}}% |
+{{- end}}
+{{- if .Groups}}
+
+## Groups
+
+| Group | Relays | Share |
+|---|---:|---:|
+{{- range .Groups}}
+| {{.Name}} | {{.Count}} | {{printf "%.2f" .Share}}% |
+{{- end}}
+{{- end}}
+
+## Bad txs by code
+
+| Code | Count | Share |
+|---|---:|---:|
+{{- range .BadTxs}}
+| {{.Name}} | {{.Count}} | {{printf "%.2f" .Share}}% |
+{{- end}}
+`
+
+const htmlSummaryTemplate = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Relay report {{.Report.BlockReport.MinHeight}} - {{.Report.BlockReport.MaxHeight}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; color: #222; }
+table { border-collapse: collapse; margin-bottom: 2em; }
+th, td { padding: 4px 12px; border-bottom: 1px solid #ddd; text-align: left; }
+td.num { text-align: right; font-variant-numeric: tabular-nums; }
+.bar { background: #3b82f6; height: 12px; }
+.chart { width: 240px; }
+</style>
+</head>
+<body>
+<h1>Relay report</h1>
+<table>
+<tr><th>Selector</th><td>{{.Report.BlockSelector}}</td></tr>
+<tr><th>Heights</th><td>{{.Report.BlockReport.MinHeight}} through {{.Report.BlockReport.MaxHeight}} (not included)</td></tr>
+{{- with .Report.Metadata}}
+<tr><th>Start block time</th><td>{{time .StartBlockTime}}</td></tr>
+<tr><th>End block time</th><td>{{time .EndBlockTime}}</td></tr>
+<tr><th>Generated at</th><td>{{.GeneratedAt.Format "2006-01-02 15:04:05 MST"}} by relay_counter {{.ToolVersion}}</td></tr>
+{{- end}}
+</table>
+<h2>Totals</h2>
+<table>
+<tr><th>Relays completed</th><td class="num">{{.Report.TotalRelaysCompleted}}</td></tr>
+<tr><th>Challenges completed</th><td class="num">{{.Report.TotalChallengesCompleted}}</td></tr>
+<tr><th>Minted</th><td class="num">{{.Report.TotalMinted}}</td></tr>
+<tr><th>Good txs</th><td class="num">{{.Report.TotalGoodTxs}}</td></tr>
+<tr><th>Bad txs</th><td class="num">{{.Report.TotalBadTxs}}</td></tr>
+<tr><th>Proof txs</th><td class="num">{{.Report.TotalProofTxs}}</td></tr>
+</table>
+{{- with .Report.Filtered}}
+<h2>Filtered totals</h2>
+<table>
+<tr><th>Relays completed</th><td class="num">{{.TotalRelaysCompleted}}</td></tr>
+<tr><th>Challenges completed</th><td class="num">{{.TotalChallengesCompleted}}</td></tr>
+<tr><th>Proof txs</th><td class="num">{{.TotalProofTxs}}</td></tr>
+</table>
+{{- end}}
+{{- define "ranked"}}
+<table>
+<tr><th>{{.Title}}</th><th>Count</th><th>Share</th><th></th></tr>
+{{- range .Rows}}
+<tr><td>{{.Name}}</td><td class="num">{{.Count}}</td><td class="num">{{printf "%.2f" .Share}}%</td><td class="chart"><div class="bar" style="width: {{printf "%.1f" .Width}}%"></div></td></tr>
+{{- end}}
+</table>
+{{- end}}
+<h2>Chains</h2>
+{{template "ranked" (section "Chain" .Chains)}}
+<h2>Top {{.TopN}} nodes</h2>
+{{template "ranked" (section "Node" .TopNodes)}}
+<h2>Top {{.TopN}} apps</h2>
+{{template "ranked" (section "App" .TopApps)}}
+{{- if .Groups}}
+<h2>Groups</h2>
+{{template "ranked" (section "Group" .Groups)}}
+{{- end}}
+<h2>Bad txs by code</h2>
+{{template "ranked" (section "Code" .BadTxs)}}
+</body>
+</html>
+`
+
+var (
+	markdownSummary = template.Must(template.New("markdown").Funcs(summaryFuncs).Parse(markdownSummaryTemplate))
+	htmlSummary     = htmltemplate.Must(htmltemplate.New("html").Funcs(summaryFuncs).Funcs(htmltemplate.FuncMap{
+		"section": func(title string, rows []RankedCount) map[string]interface{} {
+			return map[string]interface{}{"Title": title, "Rows": rows}
+		},
+	}).Parse(htmlSummaryTemplate))
+)
+
+type summaryRenderer interface {
+	Execute(w io.Writer, data interface{}) error
+}
+
+// Renders the summary of the report in markdown or html next to the json report, returns the file written
+func writeSummaryFile(result report.Report, file string, format string) (string, error) {
+	var renderer summaryRenderer
+	ext := ".md"
+	switch format {
+	case FormatMarkdown:
+		renderer = markdownSummary
+	case FormatHTML:
+		renderer, ext = htmlSummary, ".html"
+	default:
+		return "", NewInvalidFormatError(format)
+	}
+	summaryFile := strings.TrimSuffix(file, filepath.Ext(file)) + ext
+	f, err := os.Create(summaryFile)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	if err := renderer.Execute(f, NewSummary(result, SummaryTopN)); err != nil {
+		return "", err
+	}
+	return summaryFile, f.Close()
+}
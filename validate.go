@@ -0,0 +1,45 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/pokt-network/relay_counter/selector"
+)
+
+const (
+	CommandValidate = "validate"
+)
+
+// Checks the config file with the command line overrides applied, without reaching the node
+func runValidate(args []string) {
+	fs := flag.NewFlagSet(os.Args[0]+" "+CommandValidate, flag.ExitOnError)
+	cf := newConfigFlags(fs)
+	lf := newLogFlags(fs)
+	_ = fs.Parse(args)
+	lf.apply()
+
+	c, err := cf.load()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+	fmt.Printf("%s is valid\n", cf.source())
+	if c.Profile != "" {
+		fmt.Printf("profile %s\n", c.Profile)
+	}
+	switch c.Selector {
+	case selector.SelectorTimeline:
+		fmt.Printf("selects from %d to %d %s ago\n", -c.Timeline.Start, -c.Timeline.End, strings.ToLower(c.Timeline.Unit))
+	case selector.SelectorByBlock:
+		fmt.Printf("selects heights %d through %d\n", c.ByBlock.Start, c.ByBlock.End-1)
+	}
+	fmt.Printf("endpoint %s with %d retries\n", c.Endpoint, c.HTTPRetry)
+	if c.Schedule != nil {
+		for _, job := range c.Schedule.Jobs {
+			fmt.Printf("job %s runs on %q\n", job.Name, job.Cron)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pokt-network/relay_counter/indexer"
+	"github.com/pokt-network/relay_counter/logging"
+)
+
+const (
+	CommandWatch = "watch"
+)
+
+// Runs continuously, indexing new heights and periodically writing the report of the rolling window.
+// On SIGINT or SIGTERM it writes the rolling report and returns
+func runWatch(args []string) {
+	fs := flag.NewFlagSet(os.Args[0]+" "+CommandWatch, flag.ExitOnError)
+	resultDir := fs.String("resultsDir", "result", "directory the rolling reports are written to")
+	format := fs.String("format", FormatJSON, "output format of the results. It can be: json (default), csv, sqlite, markdown or html")
+	window := fs.Duration("window", 24*time.Hour, "time span of the rolling report")
+	pollInterval := fs.Duration("pollInterval", time.Minute, "time between checks for new blocks")
+	flushInterval := fs.Duration("flushInterval", time.Hour, "time between rolling report writes")
+	cf := newConfigFlags(fs)
+	lf := newLogFlags(fs)
+	_ = fs.Parse(args)
+	lf.apply()
+
+	if !isValidFormat(*format) {
+		fatal(NewInvalidFormatError(*format))
+	}
+
+	c, err := cf.load()
+	if err != nil {
+		fatal(err)
+	}
+
+	if c.Params.AppxBlockTimeInMinutes <= 0 {
+		fatal(NewInvalidBlockTimeError(c.Params.AppxBlockTimeInMinutes))
+	}
+	windowBlocks := int64(window.Minutes()) / c.Params.AppxBlockTimeInMinutes
+	if windowBlocks < 1 {
+		fatal(NewInvalidWindowError(*window, c.Params.AppxBlockTimeInMinutes))
+	}
+
+	opts, err := c.IndexerOptions()
+	if err != nil {
+		fatal(err)
+	}
+
+	ctx := interruptContext()
+	pocket := c.Client()
+	logging.Info("Testing the pocket endpoint", "endpoint", pocket.Endpoint())
+	if err := pocket.Test(ctx); err != nil {
+		fatal(err)
+	}
+
+	latestHeight, err := pocket.GetLatestHeight(ctx)
+	if err != nil {
+		fatal(err)
+	}
+	// backfill the window so the first report is complete
+	startHeight := latestHeight - windowBlocks
+	if startHeight < 1 {
+		startHeight = 1
+	}
+	logging.Info("Watching", "start_height", startHeight, "window_blocks", windowBlocks)
+	w := indexer.NewWatcher(pocket, opts, windowBlocks, startHeight)
+
+	// writes the report of the rolling window
+	flush := func(ctx context.Context) {
+		result := w.RollingReport(ctx)
+		file := filepath.Join(*resultDir, CommandWatch+"_"+time.Now().Format("01-02-06T15:04:05")+".json")
+		if *format == FormatSQLite {
+			file = filepath.Join(*resultDir, filepath.Base(DefaultSQLiteFile))
+		}
+		logging.Info("Writing the rolling report", "file", file)
+		writeReport(result, c, file, *format)
+	}
+
+	lastFlush := time.Now()
+	for {
+		indexed, err := w.Poll(ctx)
+		if ctx.Err() != nil {
+			break
+		}
+		if err != nil {
+			logging.Error("Could not poll the latest height", "err", err.Error())
+		} else if indexed != 0 {
+			logging.Info("New heights indexed", "heights", indexed, "next_height", w.NextHeight(), "total_relays", w.TotalRelays())
+		}
+		if time.Since(lastFlush) >= *flushInterval {
+			flush(ctx)
+			lastFlush = time.Now()
+		}
+		if !sleepContext(ctx, *pollInterval) {
+			break
+		}
+	}
+
+	logging.Info("Interrupted, flushing the rolling report", "next_height", w.NextHeight())
+	finishCtx, cancel := indexer.FinishContext(ctx)
+	if w.NextHeight() > startHeight {
+		flush(finishCtx)
+	}
+	cancel()
+	logging.Info("Done")
+}